@@ -0,0 +1,173 @@
+package analytics
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// DefaultDepthBps are price moves of the classic depth table, ±0.5%, ±1%, ±2% and ±5%
+var DefaultDepthBps = []int{50, 100, 200, 500}
+
+// DepthLevel is the trade needed to move price of a pool by Bps basis points
+type DepthLevel struct {
+	// price move in basis points, positive up and negative down
+	Bps int
+	// point of the moved price (rounded down), boundary of the swap
+	Point int
+	// cumulative amount paid (fee included) and acquired by a swap from the current point
+	// bounded by Point, tokenY paid for tokenX when price moves up, tokenX paid for tokenY
+	// when it moves down
+	AmountIn  *big.Int
+	AmountOut *big.Int
+}
+
+// DepthProfile is depth of a pool in both directions, levels in ascending order of move
+type DepthProfile struct {
+	Up   []DepthLevel
+	Down []DepthLevel
+}
+
+// maxAmount is uint128.max, the largest amount a swap accepts
+var maxAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// targetPoint returns the point of price of currentPoint moved by bps, rounded down
+// by calc.GetLogSqrtPriceFloor and limited to [LeftMostPt, RightMostPt] of pool
+func targetPoint(pool swap.PoolInfo, bps int) int {
+	sqrtPrice_96, _ := calc.GetSqrtPrice(pool.CurrentPoint)
+	// sqrt(price * (10000 + bps) / 10000) << 96
+	target := new(big.Int).Mul(sqrtPrice_96, sqrtPrice_96)
+	target.Mul(target, big.NewInt(int64(10000+bps)))
+	target.Div(target, big.NewInt(10000))
+	target.Sqrt(target)
+	point, err := calc.GetLogSqrtPriceFloor(target)
+	if err != nil {
+		// out of price range of the contracts
+		if bps > 0 {
+			return pool.RightMostPt
+		}
+		return pool.LeftMostPt
+	}
+	return calc.Max(pool.LeftMostPt, calc.Min(point, pool.RightMostPt))
+}
+
+// Depth returns amounts traded through liquidity and limit orders of pool to move its
+// price by each of bps basis points up and down, DefaultDepthBps if bps is empty.
+// each level is the same as a swap of uint128.max bounded by its point,
+// levels of a side are reached in a single traversal to the farthest point
+func Depth(pool swap.PoolInfo, bps []int) (DepthProfile, error) {
+	if len(bps) == 0 {
+		bps = DefaultDepthBps
+	}
+	moves := append([]int(nil), bps...)
+	sort.Ints(moves)
+	for idx, move := range moves {
+		if move <= 0 || move >= 10000 {
+			return DepthProfile{}, fmt.Errorf("price move %d bps not in (0, 10000)", move)
+		}
+		if idx > 0 && moves[idx-1] == move {
+			return DepthProfile{}, fmt.Errorf("duplicate price move %d bps", move)
+		}
+	}
+
+	up, err := depthSide(pool, moves, swap.Y2X)
+	if err != nil {
+		return DepthProfile{}, err
+	}
+	down, err := depthSide(pool, moves, swap.X2Y)
+	if err != nil {
+		return DepthProfile{}, err
+	}
+	return DepthProfile{Up: up, Down: down}, nil
+}
+
+// counted reports whether step is also taken by a swap from currentPoint bounded by point.
+// like the contracts, the swap stops once it reaches point, trading liquidity
+// there for x2y, and limit orders there only if it starts at point
+func counted(step swap.TraceStep, point, currentPoint int, isX2Y bool) bool {
+	if isX2Y {
+		if step.FromPoint == point && point == currentPoint {
+			return step.Kind == swap.StepLimitOrder
+		}
+		return step.FromPoint > point && (step.Kind != swap.StepRange || step.ToPoint >= point)
+	}
+	return step.FromPoint < point && (step.Kind != swap.StepRange || step.ToPoint <= point)
+}
+
+// depthSide swaps once to the farthest point and walks the steps, amounts of a level
+// are the steps taken before its point plus the swap from there to the point,
+// which meets no endpoint or limit order
+func depthSide(pool swap.PoolInfo, moves []int, swapType swap.SwapType) ([]DepthLevel, error) {
+	isX2Y := swapType.IsX2Y()
+	levels := make([]DepthLevel, len(moves))
+	for idx, move := range moves {
+		if isX2Y {
+			move = -move
+		}
+		levels[idx] = DepthLevel{Bps: move, Point: targetPoint(pool, move)}
+	}
+	farthest := levels[len(levels)-1]
+	_, trace, err := swap.TraceSwap(swapType, maxAmount, farthest.Point, pool)
+	if err != nil {
+		return nil, fmt.Errorf("move %d bps: %w", farthest.Bps, err)
+	}
+
+	amountIn, amountOut := big.NewInt(0), big.NewInt(0)
+	// liquidity and liquidityX before the next step
+	liquidity, liquidityX := pool.Liquidity, pool.LiquidityX
+	stepIdx := 0
+	for idx := range levels {
+		level := &levels[idx]
+		for ; stepIdx < len(trace) && counted(trace[stepIdx], level.Point, pool.CurrentPoint, isX2Y); stepIdx++ {
+			step := trace[stepIdx]
+			if step.Kind != swap.StepEndpoint {
+				stepIn, stepOut := step.AmountY, step.AmountX
+				if isX2Y {
+					stepIn, stepOut = step.AmountX, step.AmountY
+				}
+				amountIn.Add(amountIn, stepIn)
+				amountOut.Add(amountOut, stepOut)
+			}
+			liquidity, liquidityX = step.Liquidity, step.LiquidityX
+			if !isX2Y && step.Kind == swap.StepRange {
+				// y2x swap leaves the end of a passed range with all liquidity in tokenX
+				liquidityX = step.Liquidity
+			}
+		}
+		level.AmountIn = new(big.Int).Set(amountIn)
+		level.AmountOut = new(big.Int).Set(amountOut)
+		if stepIdx == len(trace) {
+			continue
+		}
+		fromPoint := trace[stepIdx].FromPoint
+		remain := new(big.Int).Sub(maxAmount, amountIn)
+		if (isX2Y && fromPoint < level.Point) || (!isX2Y && fromPoint >= level.Point) || remain.Sign() <= 0 {
+			// the point is reached
+			continue
+		}
+		start := swap.PoolInfo{
+			CurrentPoint: fromPoint,
+			PointDelta:   pool.PointDelta,
+			LeftMostPt:   pool.LeftMostPt,
+			RightMostPt:  pool.RightMostPt,
+			Fee:          pool.Fee,
+			Liquidity:    liquidity,
+			LiquidityX:   liquidityX,
+			StrictEVM:    pool.StrictEVM,
+		}
+		result, err := swap.Swap(swapType, remain, level.Point, start)
+		if err != nil {
+			return nil, fmt.Errorf("move %d bps: %w", level.Bps, err)
+		}
+		partIn, partOut := result.AmountY, result.AmountX
+		if isX2Y {
+			partIn, partOut = result.AmountX, result.AmountY
+		}
+		level.AmountIn.Add(level.AmountIn, partIn)
+		level.AmountOut.Add(level.AmountOut, partOut)
+	}
+	return levels, nil
+}
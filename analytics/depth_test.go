@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swaptest"
+)
+
+func TestDepth(t *testing.T) {
+	pool, _, _ := testPool(t)
+	pool.LimitOrders = []swap.LimitOrderPoint{
+		{Point: -80, SellingY: big.NewInt(70000000)},
+		{Point: 120, SellingX: big.NewInt(50000000), SellingY: big.NewInt(0)},
+	}
+	profile, err := Depth(pool, nil)
+	if err != nil {
+		t.Fatalf("depth failed: %v", err)
+	}
+	if len(profile.Up) != 4 || len(profile.Down) != 4 {
+		t.Fatalf("levels not expected: %+v", profile)
+	}
+	// 1.0001^49 <= 1.005 < 1.0001^50, 1.0001^-51 <= 0.995 < 1.0001^-50
+	if profile.Up[0].Point != 49 || profile.Up[0].Bps != 50 || profile.Down[0].Point != -51 || profile.Down[0].Bps != -50 {
+		t.Fatalf("0.5%% levels not expected: %+v %+v", profile.Up[0], profile.Down[0])
+	}
+	for _, side := range [][]DepthLevel{profile.Up, profile.Down} {
+		for idx := 1; idx < len(side); idx++ {
+			if side[idx].AmountIn.Cmp(side[idx-1].AmountIn) <= 0 || side[idx].AmountOut.Cmp(side[idx-1].AmountOut) <= 0 {
+				t.Fatalf("depth should be cumulative: %+v %+v", side[idx-1], side[idx])
+			}
+		}
+	}
+
+	// the limit order at 120 is passed between 0.5% and 1% up
+	if profile.Up[1].AmountOut.Cmp(new(big.Int).Add(profile.Up[0].AmountOut, big.NewInt(50000000))) <= 0 {
+		t.Fatalf("limit order should be traded in 1%% level: %+v %+v", profile.Up[0], profile.Up[1])
+	}
+
+	checkSwaps(t, pool, profile)
+
+	if _, err := Depth(pool, []int{100, 0}); err == nil {
+		t.Fatalf("zero move should fail")
+	}
+	if _, err := Depth(pool, []int{100, 100}); err == nil {
+		t.Fatalf("duplicate move should fail")
+	}
+}
+
+// checkSwaps checks every level equals a single swap to its point,
+// the last one is the swap traversed by Depth
+func checkSwaps(t *testing.T, pool swap.PoolInfo, profile DepthProfile) {
+	for _, side := range []struct {
+		swapType swap.SwapType
+		levels   []DepthLevel
+	}{
+		{swap.Y2X, profile.Up},
+		{swap.X2Y, profile.Down},
+	} {
+		for _, level := range side.levels {
+			result, err := swap.Swap(side.swapType, maxAmount, level.Point, pool)
+			if err != nil {
+				t.Fatalf("swap failed: %v", err)
+			}
+			amountIn, amountOut := result.AmountY, result.AmountX
+			if side.swapType.IsX2Y() {
+				amountIn, amountOut = result.AmountX, result.AmountY
+			}
+			if level.AmountIn.Cmp(amountIn) != 0 || level.AmountOut.Cmp(amountOut) != 0 {
+				t.Fatalf("%v level %+v not equal to single swap %s %s", side.swapType, level, amountIn, amountOut)
+			}
+		}
+	}
+}
+
+func TestDepthRandomPools(t *testing.T) {
+	for seed := int64(0); seed < 30; seed++ {
+		generator, err := swaptest.NewGenerator(seed, swaptest.Config{
+			Shape:             swaptest.Shape(seed % 3),
+			LimitOrderDensity: 0.05,
+		})
+		if err != nil {
+			t.Fatalf("new generator failed: %v", err)
+		}
+		pool, err := generator.PoolInfo()
+		if err != nil {
+			t.Fatalf("generate pool failed: %v", err)
+		}
+		profile, err := Depth(pool, []int{10, 50, 100, 200, 500, 1000})
+		if err != nil {
+			t.Fatalf("depth of seed %d failed: %v", seed, err)
+		}
+		checkSwaps(t, pool, profile)
+	}
+}
@@ -0,0 +1,201 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// DefaultBookLevels is the number of levels per side if BookConfig.Levels is 0
+const DefaultBookLevels = 20
+
+// BookConfig decides price levels of an order book, exactly one
+// of BucketPoints and BucketBps should be set
+type BookConfig struct {
+	// points per level
+	BucketPoints int
+	// price step per level in basis points of the current price
+	BucketBps int
+	// max levels per side, DefaultBookLevels if 0
+	Levels int
+}
+
+// BookLevel is a price level of an order book
+type BookLevel struct {
+	// points of the level, both included
+	LowPoint  int
+	HighPoint int
+	// price (tokenY per tokenX, without decimals) at the point of the level
+	// farthest from the current point, HighPoint for asks and LowPoint for bids
+	Price float64
+	// tokenX offered for tokenY in asks, tokenY offered for tokenX in bids,
+	// by liquidity and limit orders in the level
+	Amount *big.Int
+	// Amount of this level and all levels closer to the current point
+	Cumulative *big.Int
+}
+
+// OrderBook is a pool rendered as price levels, both sides start at the current point
+type OrderBook struct {
+	CurrentPoint int
+	// tokenX offered above the current price in ascending order of price
+	Asks []BookLevel
+	// tokenY offered below the current price in descending order of price
+	Bids []BookLevel
+}
+
+// segment is liquidity in [leftPt, rightPt)
+type segment struct {
+	leftPt, rightPt int
+	liquidity       *big.Int
+}
+
+func segments(pool swap.PoolInfo) []segment {
+	var segs []segment
+	liquidity := big.NewInt(0)
+	for idx, point := range pool.Liquidities {
+		liquidity.Add(liquidity, point.LiqudityDelta)
+		if idx+1 < len(pool.Liquidities) && liquidity.Sign() > 0 {
+			segs = append(segs, segment{point.Point, pool.Liquidities[idx+1].Point, new(big.Int).Set(liquidity)})
+		}
+	}
+	return segs
+}
+
+// liquidityAmounts returns tokenX and tokenY of liquidity in [leftPt, rightPt),
+// the current point should not be in the range
+func liquidityAmounts(segs []segment, leftPt, rightPt, currentPoint int) (*big.Int, *big.Int) {
+	amountX, amountY := big.NewInt(0), big.NewInt(0)
+	if leftPt >= rightPt {
+		return amountX, amountY
+	}
+	idx := sort.Search(len(segs), func(i int) bool {
+		return segs[i].rightPt > leftPt
+	})
+	for ; idx < len(segs) && segs[idx].leftPt < rightPt; idx++ {
+		left := calc.Max(leftPt, segs[idx].leftPt)
+		right := calc.Min(rightPt, segs[idx].rightPt)
+		x, y := rangeAmounts(segs[idx].liquidity, left, right, currentPoint)
+		amountX.Add(amountX, x)
+		amountY.Add(amountY, y)
+	}
+	return amountX, amountY
+}
+
+// bookBuckets returns points [low, high] of levels of a side
+func bookBuckets(pool swap.PoolInfo, config BookConfig, asks bool) [][2]int {
+	current := pool.CurrentPoint
+	var buckets [][2]int
+	for k := 0; len(buckets) < config.Levels; k++ {
+		var low, high int
+		if config.BucketPoints > 0 {
+			if asks {
+				low, high = current+k*config.BucketPoints, current+(k+1)*config.BucketPoints-1
+			} else {
+				low, high = current-(k+1)*config.BucketPoints+1, current-k*config.BucketPoints
+			}
+		} else {
+			if !asks && (k+1)*config.BucketBps >= 10000 {
+				break
+			}
+			// prices of the level are in [1 + k * bps, 1 + (k + 1) * bps) of the current price
+			if asks {
+				low, high = current, targetPoint(pool, (k+1)*config.BucketBps)
+				if k > 0 {
+					low = targetPoint(pool, k*config.BucketBps) + 1
+				}
+			} else {
+				low, high = targetPoint(pool, -(k+1)*config.BucketBps)+1, current
+				if k > 0 {
+					high = targetPoint(pool, -k*config.BucketBps)
+				}
+			}
+		}
+		low = calc.Max(low, pool.LeftMostPt)
+		high = calc.Min(high, pool.RightMostPt)
+		if (asks && low > pool.RightMostPt) || (!asks && high < pool.LeftMostPt) {
+			break
+		}
+		if low > high {
+			// price step is less than a point
+			if (asks && high == pool.RightMostPt) || (!asks && low == pool.LeftMostPt) {
+				break
+			}
+			continue
+		}
+		buckets = append(buckets, [2]int{low, high})
+	}
+	return buckets
+}
+
+// GetOrderBook renders pool into price levels of tokenX offered above and tokenY offered below
+// the current price, combining liquidity (as tokens it holds, rounded down) and limit orders.
+// the current point is the first level of both sides, with LiquidityX and SellingX in asks
+// and the rest of liquidity and SellingY in bids
+func GetOrderBook(pool swap.PoolInfo, config BookConfig) (OrderBook, error) {
+	if (config.BucketPoints > 0) == (config.BucketBps > 0) || config.BucketPoints < 0 || config.BucketBps < 0 {
+		return OrderBook{}, fmt.Errorf("exactly one of bucket points %d and bucket bps %d should be positive",
+			config.BucketPoints, config.BucketBps)
+	}
+	if config.Levels < 0 {
+		return OrderBook{}, fmt.Errorf("levels %d is negative", config.Levels)
+	}
+	if config.Levels == 0 {
+		config.Levels = DefaultBookLevels
+	}
+
+	current := pool.CurrentPoint
+	segs := segments(pool)
+	currentX, currentY := currentAmounts(pool)
+	book := OrderBook{CurrentPoint: current}
+	for _, asks := range []bool{true, false} {
+		cumulative := big.NewInt(0)
+		for _, bucket := range bookBuckets(pool, config, asks) {
+			low, high := bucket[0], bucket[1]
+			var amount *big.Int
+			var pricePoint int
+			if asks {
+				amount, _ = liquidityAmounts(segs, calc.Max(low, current+1), high+1, current)
+				if low <= current {
+					amount.Add(amount, currentX)
+				}
+				pricePoint = high
+			} else {
+				_, amount = liquidityAmounts(segs, low, calc.Min(high+1, current), current)
+				if high >= current {
+					amount.Add(amount, currentY)
+				}
+				pricePoint = low
+			}
+			for _, limitOrder := range pool.LimitOrders {
+				if limitOrder.Point < low || limitOrder.Point > high {
+					continue
+				}
+				if asks && limitOrder.Point >= current && limitOrder.SellingX != nil {
+					amount.Add(amount, limitOrder.SellingX)
+				}
+				if !asks && limitOrder.Point <= current && limitOrder.SellingY != nil {
+					amount.Add(amount, limitOrder.SellingY)
+				}
+			}
+			cumulative.Add(cumulative, amount)
+			level := BookLevel{
+				LowPoint:   low,
+				HighPoint:  high,
+				Price:      math.Pow(1.0001, float64(pricePoint)),
+				Amount:     amount,
+				Cumulative: new(big.Int).Set(cumulative),
+			}
+			if asks {
+				book.Asks = append(book.Asks, level)
+			} else {
+				book.Bids = append(book.Bids, level)
+			}
+		}
+	}
+	return book, nil
+}
@@ -0,0 +1,90 @@
+package analytics
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+func TestOrderBook(t *testing.T) {
+	pool, _, _ := testPool(t)
+	// half of liquidity at the current point is tokenX
+	pool.LiquidityX = new(big.Int).Div(pool.Liquidity, big.NewInt(2))
+	pool.LimitOrders = []swap.LimitOrderPoint{
+		{Point: -80, SellingY: big.NewInt(70000000)},
+		{Point: 0, SellingX: big.NewInt(11), SellingY: big.NewInt(0)},
+		{Point: 120, SellingX: big.NewInt(50000000), SellingY: big.NewInt(0)},
+	}
+
+	// levels covering the whole pool hold all reserves
+	book, err := GetOrderBook(pool, BookConfig{BucketPoints: 1000, Levels: 1000})
+	if err != nil {
+		t.Fatalf("order book failed: %v", err)
+	}
+	reserves := GetReserves(pool)
+	asks, bids := book.Asks[len(book.Asks)-1], book.Bids[len(book.Bids)-1]
+	if asks.HighPoint != pool.RightMostPt || bids.LowPoint != pool.LeftMostPt {
+		t.Fatalf("levels should reach the boundaries: %+v %+v", asks, bids)
+	}
+	for _, test := range []struct {
+		cumulative, total *big.Int
+	}{
+		{asks.Cumulative, reserves.TotalX},
+		{bids.Cumulative, reserves.TotalY},
+	} {
+		// liquidity is split at each level boundary, each part rounded down
+		diff := new(big.Int).Sub(test.total, test.cumulative)
+		if diff.Sign() < 0 || diff.Cmp(big.NewInt(20)) > 0 {
+			t.Fatalf("cumulative %s not close to reserves %s", test.cumulative, test.total)
+		}
+	}
+
+	book, err = GetOrderBook(pool, BookConfig{BucketPoints: 100, Levels: 3})
+	if err != nil {
+		t.Fatalf("order book failed: %v", err)
+	}
+	if len(book.Asks) != 3 || book.Asks[0].LowPoint != 0 || book.Asks[0].HighPoint != 99 || book.Asks[1].LowPoint != 100 ||
+		book.Bids[0].HighPoint != 0 || book.Bids[0].LowPoint != -99 || book.Bids[1].HighPoint != -100 {
+		t.Fatalf("levels not expected: %+v %+v", book.Asks, book.Bids)
+	}
+	// the limit orders are in their levels, tokenX at the current point in the first ask
+	x0, _ := liquidityAmounts(segments(pool), 1, 100, 0)
+	currentX, currentY := currentAmounts(pool)
+	if book.Asks[0].Amount.Cmp(new(big.Int).Add(new(big.Int).Add(x0, currentX), big.NewInt(11))) != 0 {
+		t.Fatalf("first ask %s not expected", book.Asks[0].Amount)
+	}
+	_, y0 := liquidityAmounts(segments(pool), -99, 0, 0)
+	if book.Bids[0].Amount.Cmp(new(big.Int).Add(new(big.Int).Add(y0, currentY), big.NewInt(70000000))) != 0 {
+		t.Fatalf("first bid %s not expected", book.Bids[0].Amount)
+	}
+	if book.Asks[1].Amount.Cmp(big.NewInt(50000000)) <= 0 ||
+		book.Asks[2].Cumulative.Cmp(new(big.Int).Add(book.Asks[1].Cumulative, book.Asks[2].Amount)) != 0 {
+		t.Fatalf("asks not expected: %+v", book.Asks)
+	}
+
+	// levels of 1% are contiguous with rising prices
+	book, err = GetOrderBook(pool, BookConfig{BucketBps: 100})
+	if err != nil {
+		t.Fatalf("order book failed: %v", err)
+	}
+	if len(book.Asks) != DefaultBookLevels || len(book.Bids) != DefaultBookLevels {
+		t.Fatalf("levels %d %d, expect %d", len(book.Asks), len(book.Bids), DefaultBookLevels)
+	}
+	// 1.0001^99 <= 1.01 < 1.0001^100
+	if book.Asks[0].LowPoint != 0 || book.Asks[0].HighPoint != 99 {
+		t.Fatalf("first ask not expected: %+v", book.Asks[0])
+	}
+	for idx := 1; idx < len(book.Asks); idx++ {
+		if book.Asks[idx].LowPoint != book.Asks[idx-1].HighPoint+1 || book.Asks[idx].Price <= book.Asks[idx-1].Price ||
+			book.Bids[idx].HighPoint != book.Bids[idx-1].LowPoint-1 || book.Bids[idx].Price >= book.Bids[idx-1].Price {
+			t.Fatalf("levels %d not contiguous: %+v %+v", idx, book.Asks[idx], book.Bids[idx])
+		}
+	}
+
+	for _, config := range []BookConfig{{}, {BucketPoints: 10, BucketBps: 10}, {BucketPoints: 10, Levels: -1}} {
+		if _, err := GetOrderBook(pool, config); err == nil {
+			t.Fatalf("config %+v should fail", config)
+		}
+	}
+}
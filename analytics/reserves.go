@@ -0,0 +1,111 @@
+// Package analytics computes views of a pool offline from swap.PoolInfo:
+// tokens locked in it, depth of price moves and an order book ladder
+package analytics
+
+import (
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/amountmath"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Reserves is tokenX and tokenY locked in a pool
+type Reserves struct {
+	// in liquidity ranges, including the current point
+	RangeX *big.Int
+	RangeY *big.Int
+	// sold by limit orders
+	OrderX *big.Int
+	OrderY *big.Int
+	// RangeX + OrderX, RangeY + OrderY
+	TotalX *big.Int
+	TotalY *big.Int
+}
+
+// rangeAmounts returns tokenX and tokenY of liquidity in [leftPt, rightPt),
+// the current point should not be in the range. amounts are rounded down like burn
+func rangeAmounts(liquidity *big.Int, leftPt, rightPt, currentPoint int) (*big.Int, *big.Int) {
+	sqrtRate_96, _ := calc.GetSqrtPrice(1)
+	sqrtPriceR_96, _ := calc.GetSqrtPrice(rightPt)
+	if rightPt <= currentPoint {
+		sqrtPriceL_96, _ := calc.GetSqrtPrice(leftPt)
+		return big.NewInt(0), amountmath.GetAmountY(liquidity, sqrtPriceL_96, sqrtPriceR_96, sqrtRate_96, false)
+	}
+	return amountmath.GetAmountX(liquidity, leftPt, rightPt, sqrtPriceR_96, sqrtRate_96, false), big.NewInt(0)
+}
+
+// currentAmounts returns tokenX of LiquidityX and tokenY of the rest of liquidity at the current point
+func currentAmounts(pool swap.PoolInfo) (*big.Int, *big.Int) {
+	if pool.Liquidity == nil || pool.LiquidityX == nil {
+		return big.NewInt(0), big.NewInt(0)
+	}
+	sqrtPrice_96, _ := calc.GetSqrtPrice(pool.CurrentPoint)
+	liquidityY := new(big.Int).Sub(pool.Liquidity, pool.LiquidityX)
+	return calc.MulDivFloor(pool.LiquidityX, utils.Pow96, sqrtPrice_96),
+		calc.MulDivFloor(liquidityY, sqrtPrice_96, utils.Pow96)
+}
+
+// GetReserves returns tokens locked in liquidity and limit orders of pool.
+// ranges between liquidity endpoints use amountmath.GetAmountX / GetAmountY,
+// the current point uses Liquidity and LiquidityX of the pool,
+// all amounts are rounded down as if withdrawn
+func GetReserves(pool swap.PoolInfo) Reserves {
+	reserves := Reserves{
+		RangeX: big.NewInt(0),
+		RangeY: big.NewInt(0),
+		OrderX: big.NewInt(0),
+		OrderY: big.NewInt(0),
+	}
+	current := pool.CurrentPoint
+	liquidity := big.NewInt(0)
+	for idx, point := range pool.Liquidities {
+		liquidity.Add(liquidity, point.LiqudityDelta)
+		if idx+1 == len(pool.Liquidities) || liquidity.Sign() <= 0 {
+			continue
+		}
+		leftPt, rightPt := point.Point, pool.Liquidities[idx+1].Point
+		// split the range around the current point
+		for _, part := range [][2]int{
+			{leftPt, calc.Min(rightPt, current)},
+			{calc.Max(leftPt, current+1), rightPt},
+		} {
+			if part[0] >= part[1] {
+				continue
+			}
+			amountX, amountY := rangeAmounts(liquidity, part[0], part[1], current)
+			reserves.RangeX.Add(reserves.RangeX, amountX)
+			reserves.RangeY.Add(reserves.RangeY, amountY)
+		}
+	}
+	amountX, amountY := currentAmounts(pool)
+	reserves.RangeX.Add(reserves.RangeX, amountX)
+	reserves.RangeY.Add(reserves.RangeY, amountY)
+
+	for _, limitOrder := range pool.LimitOrders {
+		if limitOrder.SellingX != nil {
+			reserves.OrderX.Add(reserves.OrderX, limitOrder.SellingX)
+		}
+		if limitOrder.SellingY != nil {
+			reserves.OrderY.Add(reserves.OrderY, limitOrder.SellingY)
+		}
+	}
+	reserves.TotalX = new(big.Int).Add(reserves.RangeX, reserves.OrderX)
+	reserves.TotalY = new(big.Int).Add(reserves.RangeY, reserves.OrderY)
+	return reserves
+}
+
+// ValueInY returns TotalX valued in tokenY at the price of currentPoint plus TotalY, rounded down
+func (reserves Reserves) ValueInY(currentPoint int) *big.Int {
+	sqrtPrice_96, _ := calc.GetSqrtPrice(currentPoint)
+	value := calc.MulDivFloor(calc.MulDivFloor(reserves.TotalX, sqrtPrice_96, utils.Pow96), sqrtPrice_96, utils.Pow96)
+	return value.Add(value, reserves.TotalY)
+}
+
+// ValueInX returns TotalY valued in tokenX at the price of currentPoint plus TotalX, rounded down
+func (reserves Reserves) ValueInX(currentPoint int) *big.Int {
+	sqrtPrice_96, _ := calc.GetSqrtPrice(currentPoint)
+	value := calc.MulDivFloor(calc.MulDivFloor(reserves.TotalY, utils.Pow96, sqrtPrice_96), utils.Pow96, sqrtPrice_96)
+	return value.Add(value, reserves.TotalX)
+}
@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swaptest"
+)
+
+// testPool mints ranges into an empty pool, it returns the pool
+// and tokens deposited
+func testPool(t *testing.T) (swap.PoolInfo, *big.Int, *big.Int) {
+	pool, err := swaptest.NewBuilder(2000).Build()
+	if err != nil {
+		t.Fatalf("build pool failed: %v", err)
+	}
+	depositX, depositY := big.NewInt(0), big.NewInt(0)
+	for _, r := range []struct {
+		leftPt, rightPt int
+		liquidity       int64
+	}{
+		{-4000, 4000, 1000000},
+		{-400, 400, 3000000},
+		{-8000, -2000, 500000},
+		{2000, 8000, 500000},
+	} {
+		result, err := swap.Mint(r.leftPt, r.rightPt, big.NewInt(r.liquidity), pool)
+		if err != nil {
+			t.Fatalf("mint failed: %v", err)
+		}
+		depositX.Add(depositX, result.AmountX)
+		depositY.Add(depositY, result.AmountY)
+		pool = result.Pool
+	}
+	return pool, depositX, depositY
+}
+
+func TestReserves(t *testing.T) {
+	pool, depositX, depositY := testPool(t)
+	reserves := GetReserves(pool)
+	// deposits are rounded up, reserves down, for each range and the current point
+	for _, diff := range []*big.Int{
+		new(big.Int).Sub(depositX, reserves.RangeX),
+		new(big.Int).Sub(depositY, reserves.RangeY),
+	} {
+		if diff.Sign() < 0 || diff.Cmp(big.NewInt(10)) > 0 {
+			t.Fatalf("reserves %s %s not close to deposits %s %s", reserves.RangeX, reserves.RangeY, depositX, depositY)
+		}
+	}
+
+	// reserves move from tokenY to tokenX after a swap, fee is kept out of liquidity
+	result, trace, err := swap.TraceSwap(swap.X2Y, big.NewInt(1000000000), -6000, pool)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	after := GetReserves(swap.ApplySwap(pool, result))
+	gainX := new(big.Int).Sub(after.TotalX, reserves.TotalX)
+	for _, step := range trace {
+		if step.FeeAmount != nil {
+			gainX.Add(gainX, step.FeeAmount)
+		}
+	}
+	lossY := new(big.Int).Sub(reserves.TotalY, after.TotalY)
+	if new(big.Int).Sub(gainX, result.AmountX).CmpAbs(big.NewInt(10)) > 0 ||
+		new(big.Int).Sub(lossY, result.AmountY).CmpAbs(big.NewInt(10)) > 0 {
+		t.Fatalf("reserves change %s %s not match swap %s %s", gainX, lossY, result.AmountX, result.AmountY)
+	}
+
+	pool.LimitOrders = []swap.LimitOrderPoint{
+		{Point: -400, SellingY: big.NewInt(7000)},
+		{Point: 400, SellingX: big.NewInt(5000), SellingY: big.NewInt(0)},
+	}
+	withOrders := GetReserves(pool)
+	if withOrders.OrderX.Cmp(big.NewInt(5000)) != 0 || withOrders.OrderY.Cmp(big.NewInt(7000)) != 0 ||
+		withOrders.TotalX.Cmp(new(big.Int).Add(reserves.RangeX, big.NewInt(5000))) != 0 {
+		t.Fatalf("limit orders not counted: %+v", withOrders)
+	}
+}
+
+func TestReservesValue(t *testing.T) {
+	reserves := Reserves{TotalX: big.NewInt(1000000), TotalY: big.NewInt(2000000)}
+	if value := reserves.ValueInY(0); value.Cmp(big.NewInt(3000000)) != 0 {
+		t.Fatalf("value at price 1 %s, expect 3000000", value)
+	}
+	// price of 6932 is about 2
+	valueY := reserves.ValueInY(6932)
+	valueX := reserves.ValueInX(6932)
+	if valueY.Cmp(big.NewInt(3999000)) < 0 || valueY.Cmp(big.NewInt(4001000)) > 0 ||
+		valueX.Cmp(big.NewInt(1999000)) < 0 || valueX.Cmp(big.NewInt(2001000)) > 0 {
+		t.Fatalf("value at price 2 not expected: %s in y, %s in x", valueY, valueX)
+	}
+}
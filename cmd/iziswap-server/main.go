@@ -0,0 +1,38 @@
+// Command iziswap-server serves offline quotes on pool snapshots over a json http api.
+//
+//	iziswap-server [--addr 127.0.0.1:8080] [--max-body 33554432]
+//
+// the server never calls a chain, clients upload pool snapshots
+// (json of package snapshot, with tokenX and tokenY for path quotes)
+// and quote against them, big integers are decimal strings.
+//
+//	PUT    /pools/{id}   upload or replace a snapshot
+//	GET    /pools/{id}   get a snapshot
+//	DELETE /pools/{id}   delete a snapshot
+//	GET    /pools        list pools
+//	POST   /quote        quote a swap on one pool
+//	POST   /quote/path   quote a multi-hop swap
+//	GET    /healthz      health check
+//	GET    /metrics      counters in prometheus text format
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "listen address")
+	maxBody := flag.Int64("max-body", 32<<20, "max size of request body in bytes")
+	flag.Parse()
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           NewServer(*maxBody),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("iziswap-server listening on %s", *addr)
+	log.Fatal(server.ListenAndServe())
+}
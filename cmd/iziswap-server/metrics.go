@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+var quoteKinds = []string{"pool", "path"}
+
+type requestKey struct {
+	route  string
+	status int
+}
+
+// metrics counts requests and quotes, written in prometheus text format
+type metrics struct {
+	start time.Time
+
+	mu          sync.Mutex
+	requests    map[requestKey]uint64
+	quotes      map[string]uint64
+	quoteErrors map[string]uint64
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		start:       time.Now(),
+		requests:    make(map[requestKey]uint64),
+		quotes:      make(map[string]uint64),
+		quoteErrors: make(map[string]uint64),
+	}
+}
+
+func (m *metrics) request(route string, status int) {
+	m.mu.Lock()
+	m.requests[requestKey{route: route, status: status}]++
+	m.mu.Unlock()
+}
+
+// quote counts a quote of kind "pool" or "path"
+func (m *metrics) quote(kind string, err error) {
+	m.mu.Lock()
+	m.quotes[kind]++
+	if err != nil {
+		m.quoteErrors[kind]++
+	}
+	m.mu.Unlock()
+}
+
+func (m *metrics) write(w io.Writer, pools int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP iziswap_pools number of stored pool snapshots")
+	fmt.Fprintln(w, "# TYPE iziswap_pools gauge")
+	fmt.Fprintf(w, "iziswap_pools %d\n", pools)
+
+	fmt.Fprintln(w, "# HELP iziswap_uptime_seconds seconds since the server started")
+	fmt.Fprintln(w, "# TYPE iziswap_uptime_seconds gauge")
+	fmt.Fprintf(w, "iziswap_uptime_seconds %.0f\n", time.Since(m.start).Seconds())
+
+	keys := make([]requestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].status < keys[j].status
+	})
+	fmt.Fprintln(w, "# HELP iziswap_requests_total http requests by route and status")
+	fmt.Fprintln(w, "# TYPE iziswap_requests_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "iziswap_requests_total{route=%q,status=\"%d\"} %d\n", key.route, key.status, m.requests[key])
+	}
+
+	fmt.Fprintln(w, "# HELP iziswap_quotes_total quotes by kind")
+	fmt.Fprintln(w, "# TYPE iziswap_quotes_total counter")
+	for _, kind := range quoteKinds {
+		fmt.Fprintf(w, "iziswap_quotes_total{kind=%q} %d\n", kind, m.quotes[kind])
+	}
+	fmt.Fprintln(w, "# HELP iziswap_quote_errors_total failed quotes by kind")
+	fmt.Fprintln(w, "# TYPE iziswap_quote_errors_total counter")
+	for _, kind := range quoteKinds {
+		fmt.Fprintf(w, "iziswap_quote_errors_total{kind=%q} %d\n", kind, m.quoteErrors[kind])
+	}
+}
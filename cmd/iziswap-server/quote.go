@@ -0,0 +1,247 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/quoter"
+	"github.com/izumiFinance/iZiSwap-SDK-go/router"
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// quoteRequest quotes a swap on one pool,
+// LimitPoint is the lowest point for x2y, highest point for y2x,
+// pool boundary if omitted
+type quoteRequest struct {
+	Pool       string           `json:"pool"`
+	Type       string           `json:"type"`
+	Amount     *snapshot.BigInt `json:"amount"`
+	LimitPoint *int             `json:"limitPoint"`
+	Trace      bool             `json:"trace"`
+}
+
+type quoteOutput struct {
+	Pool       string               `json:"pool"`
+	Type       string               `json:"type"`
+	Amount     *snapshot.BigInt     `json:"amount"`
+	LimitPoint int                  `json:"limitPoint"`
+	Payed      *snapshot.BigInt     `json:"payed"`
+	Acquired   *snapshot.BigInt     `json:"acquired"`
+	Result     snapshot.SwapResult  `json:"result"`
+	Trace      []snapshot.TraceStep `json:"trace,omitempty"`
+}
+
+func (server *Server) handleQuote(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	var request quoteRequest
+	if err := server.decodeBody(w, r, &request); err != nil {
+		writeError(w, err)
+		return
+	}
+	output, err := server.quote(request)
+	server.metrics.quote("pool", err)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, output)
+}
+
+func (server *Server) quote(request quoteRequest) (quoteOutput, error) {
+	swapType, err := swap.ParseSwapType(request.Type)
+	if err != nil {
+		return quoteOutput{}, badRequest("%v", err)
+	}
+	if request.Amount == nil || request.Amount.Sign() <= 0 {
+		return quoteOutput{}, badRequest("amount should be a positive decimal integer")
+	}
+	pool, err := server.pool(request.Pool)
+	if err != nil {
+		return quoteOutput{}, err
+	}
+	limitPoint := pool.info.RightMostPt
+	if swapType.IsX2Y() {
+		limitPoint = pool.info.LeftMostPt
+	}
+	if request.LimitPoint != nil {
+		limitPoint = *request.LimitPoint
+	}
+
+	amount := request.Amount.Value()
+	var result swap.SwapResult
+	var trace swap.Trace
+	if request.Trace {
+		result, trace, err = swap.TraceSwap(swapType, amount, limitPoint, pool.info)
+	} else {
+		result, err = swap.Swap(swapType, amount, limitPoint, pool.info)
+	}
+	if err != nil {
+		return quoteOutput{}, err
+	}
+	output := quoteOutput{
+		Pool:       request.Pool,
+		Type:       swapType.String(),
+		Amount:     request.Amount,
+		LimitPoint: limitPoint,
+		Payed:      snapshot.NewBigInt(result.AmountPayed(swapType)),
+		Acquired:   snapshot.NewBigInt(result.AmountAcquired(swapType)),
+		Result:     snapshot.FromSwapResult(result),
+	}
+	if request.Trace {
+		output.Trace = snapshot.FromTrace(trace)
+	}
+	return output, nil
+}
+
+// pathRequest quotes a multi-hop swap like the Quoter contract,
+// Tokens are from tokenIn to tokenOut for both modes and Pools[i]
+// is the pool swapping Tokens[i] and Tokens[i+1],
+// Amount is the input amount in mode "amount" and the desired output in mode "desire"
+type pathRequest struct {
+	Mode   string           `json:"mode"`
+	Amount *snapshot.BigInt `json:"amount"`
+	Tokens []string         `json:"tokens"`
+	Pools  []string         `json:"pools"`
+	Trace  bool             `json:"trace"`
+}
+
+type hopOutput struct {
+	Pool       string               `json:"pool"`
+	TokenIn    string               `json:"tokenIn"`
+	TokenOut   string               `json:"tokenOut"`
+	Fee        int                  `json:"fee"`
+	Type       string               `json:"type"`
+	AmountIn   *snapshot.BigInt     `json:"amountIn"`
+	AmountOut  *snapshot.BigInt     `json:"amountOut"`
+	PointAfter int                  `json:"pointAfter"`
+	Result     snapshot.SwapResult  `json:"result"`
+	Trace      []snapshot.TraceStep `json:"trace,omitempty"`
+}
+
+// pathOutput lists hops from tokenIn to tokenOut
+type pathOutput struct {
+	Mode      string           `json:"mode"`
+	Amount    *snapshot.BigInt `json:"amount"`
+	AmountIn  *snapshot.BigInt `json:"amountIn"`
+	AmountOut *snapshot.BigInt `json:"amountOut"`
+	// point of each pool after the swap, in the order of pools of request
+	PointAfterList []int       `json:"pointAfterList"`
+	Hops           []hopOutput `json:"hops"`
+}
+
+func (server *Server) handleQuotePath(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	var request pathRequest
+	if err := server.decodeBody(w, r, &request); err != nil {
+		writeError(w, err)
+		return
+	}
+	output, err := server.quotePath(request)
+	server.metrics.quote("path", err)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, output)
+}
+
+func (server *Server) quotePath(request pathRequest) (pathOutput, error) {
+	if request.Mode != "amount" && request.Mode != "desire" {
+		return pathOutput{}, badRequest("mode should be amount or desire")
+	}
+	if request.Amount == nil || request.Amount.Sign() <= 0 {
+		return pathOutput{}, badRequest("amount should be a positive decimal integer")
+	}
+	if len(request.Pools) == 0 || len(request.Tokens) != len(request.Pools)+1 {
+		return pathOutput{}, badRequest("%d tokens for %d pools, expect one more token than pools", len(request.Tokens), len(request.Pools))
+	}
+
+	// pools keep the state of the snapshot, so a pool can not be passed twice
+	pools := make([]*pool, len(request.Pools))
+	fees := make([]int, len(request.Pools))
+	used := make(map[string]bool)
+	for i, id := range request.Pools {
+		if used[id] {
+			return pathOutput{}, badRequest("pool %s used more than once in path", id)
+		}
+		used[id] = true
+		pool, err := server.pool(id)
+		if err != nil {
+			return pathOutput{}, err
+		}
+		tokenIn, tokenOut := request.Tokens[i], request.Tokens[i+1]
+		if pool.snapshot.TokenX == "" {
+			return pathOutput{}, badRequest("pool %s has no tokenX and tokenY", id)
+		}
+		if !(strings.EqualFold(tokenIn, pool.snapshot.TokenX) && strings.EqualFold(tokenOut, pool.snapshot.TokenY)) &&
+			!(strings.EqualFold(tokenIn, pool.snapshot.TokenY) && strings.EqualFold(tokenOut, pool.snapshot.TokenX)) {
+			return pathOutput{}, badRequest("pool %s does not swap %s to %s", id, tokenIn, tokenOut)
+		}
+		pools[i] = pool
+		fees[i] = pool.info.Fee
+	}
+	path, err := router.NewPath(request.Tokens, fees)
+	if err != nil {
+		return pathOutput{}, badRequest("%v", err)
+	}
+
+	// hop i of result swaps on pools[order[i]]
+	order := make([]int, len(pools))
+	for i := range order {
+		order[i] = i
+	}
+	infos := make([]swap.PoolInfo, len(pools))
+	for i, pool := range pools {
+		infos[i] = pool.info
+	}
+	var result quoter.QuoterResult
+	if request.Mode == "amount" {
+		result, err = quoter.SwapAmount(request.Amount.Value(), path, infos)
+	} else {
+		// pools are swapped from tokenOut back to tokenIn
+		for i := range order {
+			order[i] = len(pools) - 1 - i
+		}
+		result, err = quoter.SwapDesire(request.Amount.Value(), path, infos)
+	}
+	if err != nil {
+		return pathOutput{}, err
+	}
+
+	output := pathOutput{
+		Mode:           request.Mode,
+		Amount:         request.Amount,
+		PointAfterList: make([]int, len(pools)),
+		Hops:           make([]hopOutput, len(pools)),
+	}
+	for i, hop := range result.Hops {
+		idx := order[i]
+		output.PointAfterList[idx] = hop.PointAfter
+		output.Hops[idx] = hopOutput{
+			Pool:       request.Pools[idx],
+			TokenIn:    hop.TokenIn,
+			TokenOut:   hop.TokenOut,
+			Fee:        hop.Fee,
+			Type:       hop.SwapType.String(),
+			AmountIn:   snapshot.NewBigInt(hop.AmountIn),
+			AmountOut:  snapshot.NewBigInt(hop.AmountOut),
+			PointAfter: hop.PointAfter,
+			Result:     snapshot.FromSwapResult(hop.Result),
+		}
+		if request.Trace {
+			_, trace, err := swap.TraceSwap(hop.SwapType, hop.Amount, hop.BoundaryPt, pools[idx].info)
+			if err != nil {
+				return pathOutput{}, err
+			}
+			output.Hops[idx].Trace = snapshot.FromTrace(trace)
+		}
+	}
+	output.AmountIn = output.Hops[0].AmountIn
+	output.AmountOut = output.Hops[len(pools)-1].AmountOut
+	return output, nil
+}
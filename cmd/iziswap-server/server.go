@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// pool ids are used in urls
+var poolIDPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,128}$`)
+
+// pool is an uploaded snapshot with its decoded state
+type pool struct {
+	snapshot *snapshot.Snapshot
+	info     swap.PoolInfo
+}
+
+// Server is the http handler of iziswap-server
+type Server struct {
+	maxBody int64
+	mux     *http.ServeMux
+	metrics *metrics
+
+	mu    sync.RWMutex
+	pools map[string]*pool
+}
+
+// NewServer returns an empty server, request bodies larger than maxBody are rejected
+func NewServer(maxBody int64) *Server {
+	server := &Server{
+		maxBody: maxBody,
+		mux:     http.NewServeMux(),
+		metrics: newMetrics(),
+		pools:   make(map[string]*pool),
+	}
+	server.mux.HandleFunc("/healthz", server.handleHealth)
+	server.mux.HandleFunc("/metrics", server.handleMetrics)
+	server.mux.HandleFunc("/pools", server.handlePools)
+	server.mux.HandleFunc("/pools/", server.handlePool)
+	server.mux.HandleFunc("/quote", server.handleQuote)
+	server.mux.HandleFunc("/quote/path", server.handleQuotePath)
+	return server
+}
+
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	server.mux.ServeHTTP(recorder, r)
+	server.metrics.request(route(r.URL.Path), recorder.status)
+}
+
+// route returns the path used as metrics label, without pool id
+func route(path string) string {
+	switch {
+	case path == "/healthz", path == "/metrics", path == "/pools",
+		path == "/quote", path == "/quote/path":
+		return path
+	case strings.HasPrefix(path, "/pools/"):
+		return "/pools/{id}"
+	}
+	return "other"
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// httpError is an error with the status code of the response
+type httpError struct {
+	status   int
+	message  string
+	problems []string
+}
+
+func (err *httpError) Error() string {
+	return err.message
+}
+
+func badRequest(format string, args ...interface{}) *httpError {
+	return &httpError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(format string, args ...interface{}) *httpError {
+	return &httpError{status: http.StatusNotFound, message: fmt.Sprintf(format, args...)}
+}
+
+type errorOutput struct {
+	Error    string   `json:"error"`
+	Problems []string `json:"problems,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+// writeError writes err as json, errors other than *httpError are
+// errors of swap and reported as 422
+func writeError(w http.ResponseWriter, err error) {
+	var httpErr *httpError
+	if !errors.As(err, &httpErr) {
+		httpErr = &httpError{status: http.StatusUnprocessableEntity, message: err.Error()}
+	}
+	writeJSON(w, httpErr.status, errorOutput{Error: httpErr.message, Problems: httpErr.problems})
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeJSON(w, http.StatusMethodNotAllowed, errorOutput{Error: "method " + r.Method + " not allowed"})
+	return false
+}
+
+// decodeBody decodes json body into value, unknown fields are rejected
+func (server *Server) decodeBody(w http.ResponseWriter, r *http.Request, value interface{}) error {
+	body := http.MaxBytesReader(w, r.Body, server.maxBody)
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return &httpError{
+				status:  http.StatusRequestEntityTooLarge,
+				message: fmt.Sprintf("request body larger than %d bytes", server.maxBody),
+			}
+		}
+		return badRequest("decode request: %v", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return badRequest("decode request: unexpected data after json value")
+	}
+	return nil
+}
+
+func (server *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet, http.MethodHead) {
+		return
+	}
+	server.mu.RLock()
+	pools := len(server.pools)
+	server.mu.RUnlock()
+	writeJSON(w, http.StatusOK, struct {
+		Status string `json:"status"`
+		Pools  int    `json:"pools"`
+	}{Status: "ok", Pools: pools})
+}
+
+func (server *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	server.mu.RLock()
+	pools := len(server.pools)
+	server.mu.RUnlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	server.metrics.write(w, pools)
+}
+
+type poolSummary struct {
+	ID           string `json:"id"`
+	TokenX       string `json:"tokenX,omitempty"`
+	TokenY       string `json:"tokenY,omitempty"`
+	Fee          int    `json:"fee"`
+	CurrentPoint int    `json:"currentPoint"`
+	Block        uint64 `json:"block,omitempty"`
+}
+
+func summary(id string, pool *pool) poolSummary {
+	return poolSummary{
+		ID:           id,
+		TokenX:       pool.snapshot.TokenX,
+		TokenY:       pool.snapshot.TokenY,
+		Fee:          pool.snapshot.Fee,
+		CurrentPoint: pool.snapshot.CurrentPoint,
+		Block:        pool.snapshot.Block,
+	}
+}
+
+func (server *Server) handlePools(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	server.mu.RLock()
+	pools := make([]poolSummary, 0, len(server.pools))
+	for id, pool := range server.pools {
+		pools = append(pools, summary(id, pool))
+	}
+	server.mu.RUnlock()
+	sort.Slice(pools, func(i, j int) bool {
+		return pools[i].ID < pools[j].ID
+	})
+	writeJSON(w, http.StatusOK, pools)
+}
+
+func (server *Server) handlePool(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/pools/")
+	if !poolIDPattern.MatchString(id) {
+		writeError(w, badRequest("invalid pool id %q, expect 1 to 128 letters, digits or _.:-", id))
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		server.putPool(w, r, id)
+	case http.MethodGet:
+		pool, err := server.pool(id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, pool.snapshot)
+	case http.MethodDelete:
+		server.mu.Lock()
+		_, ok := server.pools[id]
+		delete(server.pools, id)
+		server.mu.Unlock()
+		if !ok {
+			writeError(w, notFound("pool %s not found", id))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		allowMethods(w, r, http.MethodPut, http.MethodGet, http.MethodDelete)
+	}
+}
+
+// putPool validates the uploaded snapshot and stores it,
+// it responds 201 for a new pool and 200 for a replaced one
+func (server *Server) putPool(w http.ResponseWriter, r *http.Request, id string) {
+	var poolSnapshot snapshot.Snapshot
+	if err := server.decodeBody(w, r, &poolSnapshot); err != nil {
+		writeError(w, err)
+		return
+	}
+	info, err := poolSnapshot.PoolInfo()
+	if err != nil {
+		writeError(w, badRequest("invalid snapshot: %v", err))
+		return
+	}
+	if err := info.Validate(); err != nil {
+		httpErr := badRequest("invalid snapshot")
+		httpErr.problems = strings.Split(err.Error(), "\n")
+		writeError(w, httpErr)
+		return
+	}
+	if (poolSnapshot.TokenX == "") != (poolSnapshot.TokenY == "") {
+		writeError(w, badRequest("invalid snapshot: tokenX and tokenY should be both set or both omitted"))
+		return
+	}
+	if poolSnapshot.TokenX != "" && strings.EqualFold(poolSnapshot.TokenX, poolSnapshot.TokenY) {
+		writeError(w, badRequest("invalid snapshot: identical tokens %s", poolSnapshot.TokenX))
+		return
+	}
+	// built once, swaps only read the bitmap
+	info.Bitmap, err = swap.BuildPointBitmap(info)
+	if err != nil {
+		writeError(w, badRequest("invalid snapshot: %v", err))
+		return
+	}
+
+	stored := &pool{snapshot: &poolSnapshot, info: info}
+	server.mu.Lock()
+	_, replaced := server.pools[id]
+	server.pools[id] = stored
+	server.mu.Unlock()
+
+	status := http.StatusCreated
+	if replaced {
+		status = http.StatusOK
+	}
+	writeJSON(w, status, summary(id, stored))
+}
+
+func (server *Server) pool(id string) (*pool, error) {
+	server.mu.RLock()
+	defer server.mu.RUnlock()
+	pool, ok := server.pools[id]
+	if !ok {
+		return nil, notFound("pool %s not found", id)
+	}
+	return pool, nil
+}
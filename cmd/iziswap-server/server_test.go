@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/quoter"
+	"github.com/izumiFinance/iZiSwap-SDK-go/router"
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swaptest"
+)
+
+const (
+	tokenA = "0x0000000000000000000000000000000000000001"
+	tokenB = "0x0000000000000000000000000000000000000002"
+	tokenC = "0x0000000000000000000000000000000000000003"
+)
+
+func testPool(t *testing.T, currentPoint int) swap.PoolInfo {
+	pool, err := swaptest.NewBuilder(2000).
+		CurrentPoint(currentPoint).
+		AddLiquidity(-5000, 5000, big.NewInt(200000000)).
+		AddLiquidity(-3000, 3000, big.NewInt(100000000)).
+		SellY(-1600, big.NewInt(100000000)).
+		SellX(1000, big.NewInt(120000000)).
+		Build()
+	if err != nil {
+		t.Fatalf("build pool failed: %v", err)
+	}
+	return pool
+}
+
+func testSnapshot(t *testing.T, currentPoint int, tokenX, tokenY string) snapshot.Snapshot {
+	poolSnapshot := snapshot.FromPoolInfo(testPool(t, currentPoint))
+	poolSnapshot.TokenX = tokenX
+	poolSnapshot.TokenY = tokenY
+	return poolSnapshot
+}
+
+func request(t *testing.T, server http.Handler, method, path string, body interface{}) (int, []byte) {
+	var reader *bytes.Reader
+	switch body := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(body))
+	default:
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal request failed: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(method, path, reader))
+	return recorder.Code, recorder.Body.Bytes()
+}
+
+func newTestServer(t *testing.T) *Server {
+	server := NewServer(1 << 20)
+	if code, body := request(t, server, http.MethodPut, "/pools/ab", testSnapshot(t, 100, tokenA, tokenB)); code != http.StatusCreated {
+		t.Fatalf("put pool ab: %d %s", code, body)
+	}
+	if code, body := request(t, server, http.MethodPut, "/pools/bc", testSnapshot(t, -200, tokenB, tokenC)); code != http.StatusCreated {
+		t.Fatalf("put pool bc: %d %s", code, body)
+	}
+	return server
+}
+
+func TestPools(t *testing.T) {
+	server := newTestServer(t)
+
+	code, body := request(t, server, http.MethodPut, "/pools/ab", testSnapshot(t, 300, tokenA, tokenB))
+	if code != http.StatusOK {
+		t.Fatalf("replace pool: %d %s", code, body)
+	}
+	code, body = request(t, server, http.MethodGet, "/pools/ab", nil)
+	if code != http.StatusOK {
+		t.Fatalf("get pool: %d %s", code, body)
+	}
+	var poolSnapshot snapshot.Snapshot
+	if err := json.Unmarshal(body, &poolSnapshot); err != nil {
+		t.Fatalf("decode pool failed: %v", err)
+	}
+	if poolSnapshot.CurrentPoint != 300 || poolSnapshot.TokenX != tokenA {
+		t.Fatalf("pool not replaced: %+v", poolSnapshot)
+	}
+
+	code, body = request(t, server, http.MethodGet, "/pools", nil)
+	var pools []poolSummary
+	if err := json.Unmarshal(body, &pools); err != nil || code != http.StatusOK {
+		t.Fatalf("list pools: %d %s", code, body)
+	}
+	if len(pools) != 2 || pools[0].ID != "ab" || pools[1].ID != "bc" {
+		t.Fatalf("pools not expected: %+v", pools)
+	}
+
+	if code, _ = request(t, server, http.MethodDelete, "/pools/bc", nil); code != http.StatusNoContent {
+		t.Fatalf("delete pool: %d", code)
+	}
+	if code, _ = request(t, server, http.MethodGet, "/pools/bc", nil); code != http.StatusNotFound {
+		t.Fatalf("get deleted pool: %d", code)
+	}
+}
+
+func TestPutInvalidPool(t *testing.T) {
+	server := NewServer(1 << 20)
+
+	broken := testSnapshot(t, 100, tokenA, tokenB)
+	broken.Liquidities[0].Point++
+	broken.CurrentPoint = 900000
+	code, body := request(t, server, http.MethodPut, "/pools/ab", broken)
+	var output errorOutput
+	if err := json.Unmarshal(body, &output); err != nil || code != http.StatusBadRequest {
+		t.Fatalf("put invalid pool: %d %s", code, body)
+	}
+	if len(output.Problems) < 2 {
+		t.Fatalf("problems of invalid pool are not reported: %s", body)
+	}
+
+	for _, body := range []string{
+		`{"currentPoint": 1`,
+		`{"unknown": 1}`,
+		`{"liquidity": "1x", "liquidityX": "0"}`,
+	} {
+		if code, _ := request(t, server, http.MethodPut, "/pools/ab", body); code != http.StatusBadRequest {
+			t.Fatalf("put %s: %d", body, code)
+		}
+	}
+	if code, _ := request(t, server, http.MethodPut, "/pools/a%20b", testSnapshot(t, 100, tokenA, tokenB)); code != http.StatusBadRequest {
+		t.Fatalf("put with invalid id: %d", code)
+	}
+
+	large := NewServer(16)
+	if code, _ := request(t, large, http.MethodPut, "/pools/ab", testSnapshot(t, 100, tokenA, tokenB)); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("put large body: %d", code)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	server := newTestServer(t)
+
+	code, body := request(t, server, http.MethodPost, "/quote", `{"pool": "ab", "type": "y2x", "amount": "150000000", "limitPoint": 4000}`)
+	if code != http.StatusOK {
+		t.Fatalf("quote: %d %s", code, body)
+	}
+	var output quoteOutput
+	if err := json.Unmarshal(body, &output); err != nil {
+		t.Fatalf("decode quote failed: %v", err)
+	}
+	expect, err := swap.SwapY2X(big.NewInt(150000000), 4000, testPool(t, 100))
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if output.Acquired.Cmp(expect.AmountX) != 0 || output.Payed.Cmp(expect.AmountY) != 0 || output.Result.CurrentPoint != expect.CurrentPoint {
+		t.Fatalf("quote %+v not match swap %+v", output, expect)
+	}
+	if output.Trace != nil {
+		t.Fatalf("trace should be omitted")
+	}
+	// big integers are decimal strings
+	if !strings.Contains(string(body), `"acquired": "`+expect.AmountX.String()+`"`) {
+		t.Fatalf("amount is not a decimal string: %s", body)
+	}
+
+	code, body = request(t, server, http.MethodPost, "/quote", `{"pool": "ab", "type": "x2y-desire", "amount": 1000, "trace": true}`)
+	output = quoteOutput{}
+	if err := json.Unmarshal(body, &output); err != nil || code != http.StatusOK {
+		t.Fatalf("quote with trace: %d %s", code, body)
+	}
+	if output.LimitPoint != testPool(t, 100).LeftMostPt || len(output.Trace) == 0 {
+		t.Fatalf("quote with trace not expected: %+v", output)
+	}
+
+	for _, test := range []struct {
+		body string
+		code int
+	}{
+		{`{"pool": "ab", "type": "x2z", "amount": "1"}`, http.StatusBadRequest},
+		{`{"pool": "ab", "type": "x2y", "amount": "0"}`, http.StatusBadRequest},
+		{`{"pool": "ab", "type": "x2y"}`, http.StatusBadRequest},
+		{`{"pool": "ab", "type": "x2y", "amount": "1", "extra": 1}`, http.StatusBadRequest},
+		{`{"pool": "cd", "type": "x2y", "amount": "1"}`, http.StatusNotFound},
+	} {
+		if code, body := request(t, server, http.MethodPost, "/quote", test.body); code != test.code {
+			t.Fatalf("quote %s: %d %s, expect %d", test.body, code, body, test.code)
+		}
+	}
+	if code, _ := request(t, server, http.MethodGet, "/quote", nil); code != http.StatusMethodNotAllowed {
+		t.Fatalf("get quote: %d", code)
+	}
+}
+
+func TestQuotePath(t *testing.T) {
+	server := newTestServer(t)
+	pools := []swap.PoolInfo{testPool(t, 100), testPool(t, -200)}
+
+	code, body := request(t, server, http.MethodPost, "/quote/path", pathRequest{
+		Mode:   "amount",
+		Amount: snapshot.NewBigInt(big.NewInt(10000000)),
+		Tokens: []string{tokenA, tokenB, tokenC},
+		Pools:  []string{"ab", "bc"},
+		Trace:  true,
+	})
+	var output pathOutput
+	if err := json.Unmarshal(body, &output); err != nil || code != http.StatusOK {
+		t.Fatalf("quote path amount: %d %s", code, body)
+	}
+	path, _ := router.NewPath([]string{tokenA, tokenB, tokenC}, []int{2000, 2000})
+	expect, err := quoter.SwapAmount(big.NewInt(10000000), path, pools)
+	if err != nil {
+		t.Fatalf("quoter failed: %v", err)
+	}
+	if output.AmountOut.Cmp(expect.Amount) != 0 || output.Hops[0].Type != "X2Y" || output.Hops[1].Type != "X2Y" {
+		t.Fatalf("quote path %+v not match quoter %+v", output, expect)
+	}
+	if len(output.Hops[0].Trace) == 0 || len(output.Hops[1].Trace) == 0 {
+		t.Fatalf("trace of hops is missing")
+	}
+
+	// desire from C to A, hops are listed from tokenIn to tokenOut
+	code, body = request(t, server, http.MethodPost, "/quote/path", pathRequest{
+		Mode:   "desire",
+		Amount: snapshot.NewBigInt(big.NewInt(10000000)),
+		Tokens: []string{tokenC, tokenB, tokenA},
+		Pools:  []string{"bc", "ab"},
+	})
+	output = pathOutput{}
+	if err := json.Unmarshal(body, &output); err != nil || code != http.StatusOK {
+		t.Fatalf("quote path desire: %d %s", code, body)
+	}
+	desirePath, _ := router.NewPath([]string{tokenC, tokenB, tokenA}, []int{2000, 2000})
+	expect, err = quoter.SwapDesire(big.NewInt(10000000), desirePath, []swap.PoolInfo{pools[1], pools[0]})
+	if err != nil {
+		t.Fatalf("quoter failed: %v", err)
+	}
+	if output.AmountIn.Cmp(expect.Amount) != 0 || output.AmountOut.Cmp(big.NewInt(10000000)) < 0 {
+		t.Fatalf("quote path %+v not match quoter %+v", output, expect)
+	}
+	if output.Hops[0].Pool != "bc" || output.Hops[0].TokenIn != tokenC || output.PointAfterList[1] != expect.PointAfterList[0] {
+		t.Fatalf("hops are not in order of request: %+v", output)
+	}
+
+	for _, test := range []struct {
+		request pathRequest
+		code    int
+	}{
+		{pathRequest{Mode: "exact", Amount: snapshot.NewBigInt(big.NewInt(1)), Tokens: []string{tokenA, tokenB}, Pools: []string{"ab"}}, http.StatusBadRequest},
+		{pathRequest{Mode: "amount", Amount: snapshot.NewBigInt(big.NewInt(1)), Tokens: []string{tokenA}, Pools: []string{"ab"}}, http.StatusBadRequest},
+		{pathRequest{Mode: "amount", Amount: snapshot.NewBigInt(big.NewInt(1)), Tokens: []string{tokenA, tokenC}, Pools: []string{"ab"}}, http.StatusBadRequest},
+		{pathRequest{Mode: "amount", Amount: snapshot.NewBigInt(big.NewInt(1)), Tokens: []string{tokenA, tokenB, tokenA}, Pools: []string{"ab", "ab"}}, http.StatusBadRequest},
+		{pathRequest{Mode: "amount", Amount: snapshot.NewBigInt(big.NewInt(1)), Tokens: []string{tokenA, tokenB}, Pools: []string{"cd"}}, http.StatusNotFound},
+	} {
+		if code, body := request(t, server, http.MethodPost, "/quote/path", test.request); code != test.code {
+			t.Fatalf("quote path %+v: %d %s, expect %d", test.request, code, body, test.code)
+		}
+	}
+}
+
+func TestHealthAndMetrics(t *testing.T) {
+	server := newTestServer(t)
+	if code, body := request(t, server, http.MethodGet, "/healthz", nil); code != http.StatusOK || !strings.Contains(string(body), `"ok"`) {
+		t.Fatalf("healthz: %d %s", code, body)
+	}
+	request(t, server, http.MethodPost, "/quote", `{"pool": "ab", "type": "x2y", "amount": "1000"}`)
+	request(t, server, http.MethodPost, "/quote", `{"pool": "cd", "type": "x2y", "amount": "1000"}`)
+
+	code, body := request(t, server, http.MethodGet, "/metrics", nil)
+	if code != http.StatusOK {
+		t.Fatalf("metrics: %d", code)
+	}
+	for _, line := range []string{
+		"iziswap_pools 2",
+		`iziswap_requests_total{route="/pools/{id}",status="201"} 2`,
+		`iziswap_requests_total{route="/quote",status="404"} 1`,
+		`iziswap_quotes_total{kind="pool"} 2`,
+		`iziswap_quote_errors_total{kind="pool"} 1`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Fatalf("metrics has no line %s:\n%s", line, body)
+		}
+	}
+}
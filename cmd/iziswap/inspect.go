@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"text/tabwriter"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+type validateOutput struct {
+	Valid    bool     `json:"valid"`
+	Problems []string `json:"problems"`
+}
+
+// errInvalid is returned for a snapshot failing Validate, validate prints
+// the problems before, quote and trace refuse to swap on it
+var errInvalid = errors.New("invalid snapshot")
+
+func (cmd *command) validate(args []string) error {
+	flags := cmd.flagSet("validate")
+	if positional, err := parse(flags, args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return fmt.Errorf("%w: unexpected argument %s", errUsage, positional[0])
+	}
+	pool, err := cmd.readPool()
+	if err != nil {
+		return err
+	}
+	output := validateOutput{Valid: true, Problems: []string{}}
+	if err := pool.Validate(); err != nil {
+		output.Valid = false
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, problem := range joined.Unwrap() {
+				output.Problems = append(output.Problems, problem.Error())
+			}
+		} else {
+			output.Problems = append(output.Problems, err.Error())
+		}
+	}
+	if cmd.json {
+		if err := cmd.printJSON(output); err != nil {
+			return err
+		}
+	} else if output.Valid {
+		fmt.Fprintln(cmd.stdout, "ok")
+	} else {
+		for _, problem := range output.Problems {
+			fmt.Fprintln(cmd.stdout, problem)
+		}
+	}
+	if !output.Valid {
+		return errInvalid
+	}
+	return nil
+}
+
+// liquidityRange is the liquidity in [LeftPt, RightPt)
+type liquidityRange struct {
+	LeftPt    int              `json:"leftPt"`
+	RightPt   int              `json:"rightPt"`
+	Liquidity *snapshot.BigInt `json:"liquidity"`
+}
+
+type inspectOutput struct {
+	CurrentPoint int              `json:"currentPoint"`
+	SqrtPrice_96 *snapshot.BigInt `json:"sqrtPrice_96"`
+	// price of tokenX in tokenY, without decimals of tokens
+	Price       float64               `json:"price"`
+	Fee         int                   `json:"fee"`
+	PointDelta  int                   `json:"pointDelta"`
+	LeftMostPt  int                   `json:"leftMostPt"`
+	RightMostPt int                   `json:"rightMostPt"`
+	Liquidity   *snapshot.BigInt      `json:"liquidity"`
+	LiquidityX  *snapshot.BigInt      `json:"liquidityX"`
+	Ranges      []liquidityRange      `json:"ranges"`
+	LimitOrders []snapshot.LimitOrder `json:"limitOrders"`
+}
+
+func (cmd *command) inspect(args []string) error {
+	flags := cmd.flagSet("inspect")
+	if positional, err := parse(flags, args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return fmt.Errorf("%w: unexpected argument %s", errUsage, positional[0])
+	}
+	pool, err := cmd.readPool()
+	if err != nil {
+		return err
+	}
+	sqrtPrice_96, err := calc.GetSqrtPrice(pool.CurrentPoint)
+	if err != nil {
+		return fmt.Errorf("currentPoint %d out of range", pool.CurrentPoint)
+	}
+	poolSnapshot := snapshot.FromPoolInfo(pool)
+	output := inspectOutput{
+		CurrentPoint: pool.CurrentPoint,
+		SqrtPrice_96: snapshot.NewBigInt(sqrtPrice_96),
+		Price:        math.Pow(1.0001, float64(pool.CurrentPoint)),
+		Fee:          pool.Fee,
+		PointDelta:   pool.PointDelta,
+		LeftMostPt:   pool.LeftMostPt,
+		RightMostPt:  pool.RightMostPt,
+		Liquidity:    poolSnapshot.Liquidity,
+		LiquidityX:   poolSnapshot.LiquidityX,
+		Ranges:       liquidityRanges(pool.Liquidities),
+		LimitOrders:  poolSnapshot.LimitOrders,
+	}
+	if cmd.json {
+		return cmd.printJSON(output)
+	}
+
+	writer := tabwriter.NewWriter(cmd.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(writer, "current point\t%d\n", output.CurrentPoint)
+	fmt.Fprintf(writer, "price\t%g tokenY per tokenX (without decimals)\n", output.Price)
+	fmt.Fprintf(writer, "sqrtPrice_96\t%s\n", sqrtPrice_96)
+	fmt.Fprintf(writer, "fee\t%d (%g%%)\n", pool.Fee, float64(pool.Fee)/1e4)
+	fmt.Fprintf(writer, "point delta\t%d\n", pool.PointDelta)
+	fmt.Fprintf(writer, "most points\t[%d, %d]\n", pool.LeftMostPt, pool.RightMostPt)
+	fmt.Fprintf(writer, "liquidity\t%s\n", pool.Liquidity)
+	fmt.Fprintf(writer, "liquidityX\t%s\n", pool.LiquidityX)
+	fmt.Fprintf(writer, "\nliquidity distribution:\n")
+	for _, rg := range output.Ranges {
+		marker := ""
+		if rg.LeftPt <= pool.CurrentPoint && pool.CurrentPoint < rg.RightPt {
+			marker = "  <- current"
+		}
+		fmt.Fprintf(writer, "  [%d, %d)\t%s%s\n", rg.LeftPt, rg.RightPt, rg.Liquidity, marker)
+	}
+	fmt.Fprintf(writer, "\nlimit orders:\n")
+	for _, limitOrder := range pool.LimitOrders {
+		if limitOrder.SellingX != nil && limitOrder.SellingX.Sign() > 0 {
+			fmt.Fprintf(writer, "  %d\tsellingX %s\n", limitOrder.Point, limitOrder.SellingX)
+		}
+		if limitOrder.SellingY != nil && limitOrder.SellingY.Sign() > 0 {
+			fmt.Fprintf(writer, "  %d\tsellingY %s\n", limitOrder.Point, limitOrder.SellingY)
+		}
+	}
+	return writer.Flush()
+}
+
+// liquidityRanges accumulates liquidity deltas into ranges of constant liquidity
+func liquidityRanges(liquidities []swap.LiquidityPoint) []liquidityRange {
+	ranges := []liquidityRange{}
+	liquidity := big.NewInt(0)
+	for idx, point := range liquidities {
+		liquidity.Add(liquidity, point.LiqudityDelta)
+		if idx+1 < len(liquidities) && liquidity.Sign() != 0 {
+			ranges = append(ranges, liquidityRange{
+				LeftPt:    point.Point,
+				RightPt:   liquidities[idx+1].Point,
+				Liquidity: snapshot.NewBigInt(liquidity),
+			})
+		}
+	}
+	return ranges
+}
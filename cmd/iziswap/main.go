@@ -0,0 +1,122 @@
+// Command iziswap quotes swaps on a pool snapshot offline.
+//
+//	iziswap quote x2y|y2x|x2y-desire|y2x-desire --amount N [--limit-point P] [--snapshot file] [--json]
+//	iziswap trace x2y|y2x|x2y-desire|y2x-desire --amount N [--limit-point P] [--snapshot file] [--json]
+//	iziswap validate [--snapshot file] [--json]
+//	iziswap inspect [--snapshot file] [--json]
+//
+// the snapshot is a json file in the format of package snapshot,
+// it is read from stdin if --snapshot is omitted or "-"
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+const usage = `usage:
+  iziswap quote x2y|y2x|x2y-desire|y2x-desire --amount N [--limit-point P] [--snapshot file] [--json]
+  iziswap trace x2y|y2x|x2y-desire|y2x-desire --amount N [--limit-point P] [--snapshot file] [--json]
+  iziswap validate [--snapshot file] [--json]
+  iziswap inspect [--snapshot file] [--json]
+`
+
+// errUsage is returned for wrong command line, the exit code is 2
+var errUsage = errors.New("wrong usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes command args and returns exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd := &command{stdin: stdin, stdout: stdout}
+	var err error
+	switch args[0] {
+	case "quote":
+		err = cmd.quote(args[1:], false)
+	case "trace":
+		err = cmd.quote(args[1:], true)
+	case "validate":
+		err = cmd.validate(args[1:])
+	case "inspect":
+		err = cmd.inspect(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		err = fmt.Errorf("%w: unknown command %s", errUsage, args[0])
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "iziswap: %v\n", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+type command struct {
+	stdin  io.Reader
+	stdout io.Writer
+
+	snapshotPath string
+	json         bool
+}
+
+// flagSet returns flags shared by all commands
+func (cmd *command) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&cmd.snapshotPath, "snapshot", "-", "pool snapshot file, - for stdin")
+	flags.BoolVar(&cmd.json, "json", false, "print json")
+	return flags
+}
+
+// parse parses flags mixed with positional arguments
+func parse(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+func (cmd *command) readPool() (swap.PoolInfo, error) {
+	var pool *snapshot.Snapshot
+	var err error
+	if cmd.snapshotPath == "-" {
+		pool, err = snapshot.Decode(cmd.stdin)
+	} else {
+		pool, err = snapshot.ReadFile(cmd.snapshotPath)
+	}
+	if err != nil {
+		return swap.PoolInfo{}, err
+	}
+	return pool.PoolInfo()
+}
+
+func (cmd *command) printJSON(value interface{}) error {
+	encoder := json.NewEncoder(cmd.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swaptest"
+)
+
+func testSnapshot(t *testing.T) string {
+	pool, err := swaptest.NewBuilder(2000).
+		CurrentPoint(100).
+		AddLiquidity(-5000, 5000, big.NewInt(200000)).
+		AddLiquidity(-3000, 3000, big.NewInt(100000)).
+		LiquidityX(big.NewInt(100000)).
+		SellY(-1600, big.NewInt(100000000000)).
+		SellX(1000, big.NewInt(120000000000)).
+		Build()
+	if err != nil {
+		t.Fatalf("build pool failed: %v", err)
+	}
+	data, err := json.Marshal(snapshot.FromPoolInfo(pool))
+	if err != nil {
+		t.Fatalf("marshal snapshot failed: %v", err)
+	}
+	return string(data)
+}
+
+func runCommand(t *testing.T, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestQuote(t *testing.T) {
+	pool := testSnapshot(t)
+	code, stdout, stderr := runCommand(t, pool, "quote", "y2x", "--amount", "150000000000", "--limit-point", "5000")
+	if code != 0 {
+		t.Fatalf("quote failed: %s", stderr)
+	}
+	if !strings.Contains(stdout, "payed") || !strings.Contains(stdout, "tokenX") {
+		t.Fatalf("quote output not expected:\n%s", stdout)
+	}
+
+	code, stdout, stderr = runCommand(t, pool, "quote", "--json", "--amount", "150000000000", "x2y-desire")
+	if code != 0 {
+		t.Fatalf("quote failed: %s", stderr)
+	}
+	var output quoteOutput
+	if err := json.Unmarshal([]byte(stdout), &output); err != nil {
+		t.Fatalf("decode quote output failed: %v", err)
+	}
+	if output.Type != "X2YDesireY" || output.LimitPoint != -800000 || output.Payed.Sign() <= 0 {
+		t.Fatalf("quote output not expected: %+v", output)
+	}
+	if output.Trace != nil {
+		t.Fatalf("quote should not print trace")
+	}
+
+	code, stdout, _ = runCommand(t, pool, "trace", "x2y", "--amount", "150000000000", "--json")
+	if code != 0 || json.Unmarshal([]byte(stdout), &output) != nil || len(output.Trace) == 0 {
+		t.Fatalf("trace output not expected:\n%s", stdout)
+	}
+
+	// invalid pool is rejected before swapping
+	broken := strings.Replace(pool, `"pointDelta":40`, `"pointDelta":0`, 1)
+	for _, command := range []string{"quote", "trace"} {
+		code, _, stderr = runCommand(t, broken, command, "x2y", "--amount", "150000000000")
+		if code != 1 || !strings.Contains(stderr, "pointDelta 0 is not positive") {
+			t.Fatalf("%s of invalid pool not expected (%d):\n%s", command, code, stderr)
+		}
+	}
+}
+
+func TestUsage(t *testing.T) {
+	pool := testSnapshot(t)
+	for _, args := range [][]string{
+		{},
+		{"swap"},
+		{"quote", "--amount", "100"},
+		{"quote", "z2y", "--amount", "100"},
+		{"quote", "x2y", "--amount", "-100"},
+		{"quote", "x2y", "--amount", "100", "--unknown"},
+	} {
+		if code, _, _ := runCommand(t, pool, args...); code != 2 {
+			t.Fatalf("args %v exit with %d, expect 2", args, code)
+		}
+	}
+	if code, _, _ := runCommand(t, "{", "inspect"); code != 1 {
+		t.Fatalf("broken snapshot exit with %d, expect 1", code)
+	}
+}
+
+func TestValidateInspect(t *testing.T) {
+	pool := testSnapshot(t)
+	if code, stdout, _ := runCommand(t, pool, "validate"); code != 0 || strings.TrimSpace(stdout) != "ok" {
+		t.Fatalf("validate output not expected (%d):\n%s", code, stdout)
+	}
+	broken := strings.Replace(pool, `"liquidity":"300000"`, `"liquidity":"1"`, 1)
+	code, stdout, _ := runCommand(t, broken, "validate", "--json")
+	var output validateOutput
+	if code != 1 || json.Unmarshal([]byte(stdout), &output) != nil || output.Valid || len(output.Problems) == 0 {
+		t.Fatalf("validate output not expected (%d):\n%s", code, stdout)
+	}
+
+	code, stdout, _ = runCommand(t, pool, "inspect", "--json")
+	var inspect inspectOutput
+	if code != 0 || json.Unmarshal([]byte(stdout), &inspect) != nil {
+		t.Fatalf("inspect output not expected (%d):\n%s", code, stdout)
+	}
+	if len(inspect.Ranges) != 3 || len(inspect.LimitOrders) != 2 || inspect.CurrentPoint != 100 {
+		t.Fatalf("inspect output not expected: %+v", inspect)
+	}
+}
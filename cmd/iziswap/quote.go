@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"text/tabwriter"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+type quoteOutput struct {
+	Type       string               `json:"type"`
+	Amount     *snapshot.BigInt     `json:"amount"`
+	LimitPoint int                  `json:"limitPoint"`
+	Payed      *snapshot.BigInt     `json:"payed"`
+	Acquired   *snapshot.BigInt     `json:"acquired"`
+	Result     snapshot.SwapResult  `json:"result"`
+	Trace      []snapshot.TraceStep `json:"trace,omitempty"`
+}
+
+// quote runs quote and trace commands
+func (cmd *command) quote(args []string, withTrace bool) error {
+	flags := cmd.flagSet("quote")
+	amountText := flags.String("amount", "", "input amount, or desired amount for desire swaps")
+	limitPoint := flags.Int("limit-point", 0, "lowest point for x2y, highest point for y2x, pool boundary by default")
+	positional, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("%w: swap type x2y, y2x, x2y-desire or y2x-desire is required", errUsage)
+	}
+	swapType, err := swap.ParseSwapType(positional[0])
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	amount, ok := new(big.Int).SetString(*amountText, 10)
+	if !ok || amount.Sign() <= 0 {
+		return fmt.Errorf("%w: --amount should be a positive decimal integer", errUsage)
+	}
+	limitPointSet := false
+	flags.Visit(func(f *flag.Flag) {
+		limitPointSet = limitPointSet || f.Name == "limit-point"
+	})
+
+	pool, err := cmd.readPool()
+	if err != nil {
+		return err
+	}
+	// swapping on an invalid pool may panic, e.g. with zero pointDelta
+	if err := pool.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", errInvalid, err)
+	}
+	if !limitPointSet {
+		*limitPoint = pool.RightMostPt
+		if swapType.IsX2Y() {
+			*limitPoint = pool.LeftMostPt
+		}
+	}
+
+	var result swap.SwapResult
+	var trace swap.Trace
+	if withTrace {
+		result, trace, err = swap.TraceSwap(swapType, amount, *limitPoint, pool)
+	} else {
+		result, err = swap.Swap(swapType, amount, *limitPoint, pool)
+	}
+	if err != nil {
+		return err
+	}
+
+	output := quoteOutput{
+		Type:       swapType.String(),
+		Amount:     snapshot.NewBigInt(amount),
+		LimitPoint: *limitPoint,
+		Payed:      snapshot.NewBigInt(result.AmountPayed(swapType)),
+		Acquired:   snapshot.NewBigInt(result.AmountAcquired(swapType)),
+		Result:     snapshot.FromSwapResult(result),
+	}
+	if withTrace {
+		output.Trace = snapshot.FromTrace(trace)
+	}
+	if cmd.json {
+		return cmd.printJSON(output)
+	}
+
+	tokenIn, tokenOut := "tokenY", "tokenX"
+	if swapType.IsX2Y() {
+		tokenIn, tokenOut = "tokenX", "tokenY"
+	}
+	writer := tabwriter.NewWriter(cmd.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(writer, "swap\t%s\n", swapType)
+	fmt.Fprintf(writer, "amount\t%s\n", amount)
+	fmt.Fprintf(writer, "payed\t%s %s\n", output.Payed, tokenIn)
+	fmt.Fprintf(writer, "acquired\t%s %s\n", output.Acquired, tokenOut)
+	fmt.Fprintf(writer, "remain\t%s\n", result.AmountRemain)
+	fmt.Fprintf(writer, "point\t%d -> %d (limit %d)\n", pool.CurrentPoint, result.CurrentPoint, *limitPoint)
+	fmt.Fprintf(writer, "liquidity\t%s\n", result.Liquidity)
+	fmt.Fprintf(writer, "liquidityX\t%s\n", result.LiquidityX)
+	fmt.Fprintf(writer, "stop reason\t%s\n", result.StopReason)
+	fmt.Fprintf(writer, "gas\t%d (estimated)\n", output.Result.Gas)
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	if withTrace {
+		fmt.Fprintf(cmd.stdout, "trace:\n%s\n", trace)
+	}
+	return nil
+}
@@ -1,3 +1,7 @@
 module github.com/izumiFinance/iZiSwap-SDK-go
 
 go 1.20
+
+require golang.org/x/crypto v0.9.0
+
+require golang.org/x/sys v0.8.0 // indirect
@@ -0,0 +1,88 @@
+package golden
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGoldenVectors(t *testing.T) {
+	vectors, err := LoadDir("testdata")
+	if err != nil {
+		t.Fatalf("load vectors failed: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatalf("no vector in testdata")
+	}
+	for _, vector := range vectors {
+		vector := vector
+		t.Run(vector.Name, func(t *testing.T) {
+			if report := Replay(vector); report.Failed() {
+				t.Fatalf("%s", report)
+			}
+		})
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	vector, err := ReadFile("testdata/x2y-partial-liquidity.json")
+	if err != nil {
+		t.Fatalf("read vector failed: %v", err)
+	}
+	vector.Expect.AmountY.Add(&vector.Expect.AmountY.Int, big.NewInt(1))
+	vector.Expect.CurrentPoint++
+	report := Replay(vector)
+	if !report.Failed() || len(report.Mismatches) != 2 {
+		t.Fatalf("expect 2 mismatches, got %v", report.Mismatches)
+	}
+	if len(report.Trace) == 0 {
+		t.Fatalf("trace of mismatched vector is empty")
+	}
+}
+
+func TestOnChainVectors(t *testing.T) {
+	vectors, err := LoadDir("testdata")
+	if err != nil {
+		t.Fatalf("load vectors failed: %v", err)
+	}
+	onChain := 0
+	for _, vector := range vectors {
+		if vector.Source.OnChain() {
+			onChain++
+		}
+	}
+	if onChain == 0 {
+		t.Skip("no vector recorded on chain in testdata, all vectors are synthetic")
+	}
+}
+
+func TestReadFileSource(t *testing.T) {
+	data, err := os.ReadFile("testdata/x2y-partial-liquidity.json")
+	if err != nil {
+		t.Fatalf("read vector failed: %v", err)
+	}
+	synthetic := `"source": {
+    "synthetic": "pool contract test suite, same case as TestSwapDetailX2Y1"
+  }`
+	for _, test := range []struct {
+		source string
+		valid  bool
+	}{
+		{`"source": {"chain": "bsc", "block": 28000000, "tx": "0x` + strings.Repeat("ab", 32) + `"}`, true},
+		{`"source": {"chain": "bsc", "block": 28000000}`, false},
+		{`"source": {"chain": "bsc", "tx": "0x` + strings.Repeat("ab", 32) + `"}`, false},
+		{`"source": {"block": 28000000, "tx": "0x1234"}`, false},
+		{`"source": {"synthetic": "test", "chain": "bsc"}`, false},
+		{`"source": {}`, false},
+	} {
+		path := filepath.Join(t.TempDir(), "vector.json")
+		if err := os.WriteFile(path, []byte(strings.Replace(string(data), synthetic, test.source, 1)), 0o644); err != nil {
+			t.Fatalf("write vector failed: %v", err)
+		}
+		if _, err := ReadFile(path); (err == nil) != test.valid {
+			t.Fatalf("source %s: valid %v, got error %v", test.source, test.valid, err)
+		}
+	}
+}
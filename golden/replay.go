@@ -0,0 +1,75 @@
+package golden
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Report is the result of replaying a vector
+type Report struct {
+	Name       string
+	Result     swap.SwapResult
+	Trace      swap.Trace
+	Mismatches []string
+	// error of decoding the vector or of the swap
+	Err error
+}
+
+// Failed reports whether the sdk does not reproduce the vector
+func (report *Report) Failed() bool {
+	return report.Err != nil || len(report.Mismatches) > 0
+}
+
+func (report *Report) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "vector %s", report.Name)
+	if report.Err != nil {
+		fmt.Fprintf(&builder, ": %v", report.Err)
+	}
+	for _, mismatch := range report.Mismatches {
+		fmt.Fprintf(&builder, "\n  %s", mismatch)
+	}
+	if len(report.Trace) > 0 {
+		fmt.Fprintf(&builder, "\ntrace:\n%s", report.Trace)
+	}
+	return builder.String()
+}
+
+func (report *Report) compareInt(field string, expect, actual *big.Int) {
+	if expect != nil && (actual == nil || expect.Cmp(actual) != 0) {
+		report.Mismatches = append(report.Mismatches,
+			fmt.Sprintf("%s mismatch: expect %s, sdk %s", field, expect, actual))
+	}
+}
+
+// Replay runs the swap of vector through the sdk and compares the result
+func Replay(vector *Vector) *Report {
+	report := &Report{Name: vector.Name}
+	swapType, err := swap.ParseSwapType(vector.Swap.Type)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	pool, err := vector.Pool.PoolInfo()
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	report.Result, report.Trace, report.Err = swap.TraceSwap(swapType, vector.Swap.Amount.Value(), vector.Swap.BoundaryPt, pool)
+	if report.Err != nil {
+		return report
+	}
+	expect := vector.Expect
+	report.compareInt("amountX", expect.AmountX.Value(), report.Result.AmountX)
+	report.compareInt("amountY", expect.AmountY.Value(), report.Result.AmountY)
+	if expect.CurrentPoint != report.Result.CurrentPoint {
+		report.Mismatches = append(report.Mismatches,
+			fmt.Sprintf("currentPoint mismatch: expect %d, sdk %d", expect.CurrentPoint, report.Result.CurrentPoint))
+	}
+	report.compareInt("liquidity", expect.Liquidity.Value(), report.Result.Liquidity)
+	report.compareInt("liquidityX", expect.LiquidityX.Value(), report.Result.LiquidityX)
+	return report
+}
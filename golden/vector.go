@@ -0,0 +1,156 @@
+// Package golden replays recorded swaps (golden vectors) through the sdk.
+//
+// A vector is a json file, big integers are decimal strings:
+//
+//	{
+//	  "name": "bsc-usdt-wbnb-2000-incident",
+//	  "description": "x2y crossing a limit order at the current point",
+//	  "source": {"chain": "bsc", "block": 28000000, "tx": "0x..."},
+//	  "pool": {
+//	    "chain": "bsc",
+//	    "pool": "0x...",
+//	    "block": 28000000,
+//	    "currentPoint": 1729,
+//	    "pointDelta": 40,
+//	    "leftMostPt": -800000,
+//	    "rightMostPt": 800000,
+//	    "fee": 2000,
+//	    "liquidity": "500000",
+//	    "liquidityX": "134333",
+//	    "liquidities": [{"point": -7000, "liquidDelta": "200000"}],
+//	    "limitOrders": [{"point": -6200, "sellingY": "100000000000"}]
+//	  },
+//	  "swap": {"type": "x2y", "amount": "462592000000", "boundaryPt": -6789},
+//	  "expect": {
+//	    "amountX": "462592000000",
+//	    "amountY": "372866052521",
+//	    "currentPoint": -6786,
+//	    "liquidity": "200000",
+//	    "liquidityX": "151638"
+//	  }
+//	}
+//
+// "source" of a vector recorded on chain has the chain, the block and the hash of
+// the transaction of the swap. a vector not recorded on chain, such as a case of
+// the contract test suite, has "synthetic" describing its origin instead:
+//
+//	"source": {"synthetic": "pool contract test suite, TestSwapDetailX2Y1"}
+//
+// "pool" is the pool state at block "block" before the swap, in the format
+// of package snapshot, it should contain all liquidities and limit orders
+// between currentPoint and boundaryPt.
+// "swap.type" is one of x2y, y2x, x2y-desire and y2x-desire,
+// "swap.amount" is the input amount, or the desired amount for desire swaps,
+// "swap.boundaryPt" is lowPt for x2y swaps and highPt for y2x swaps.
+// "expect" is the on-chain result of the swap: amounts of tokenX and tokenY
+// paid or acquired, and currentPoint, liquidity and liquidityX of the pool after
+// the swap, "liquidity" may be omitted.
+package golden
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/snapshot"
+)
+
+// Call is the parameters of a recorded swap
+type Call struct {
+	Type       string           `json:"type"`
+	Amount     *snapshot.BigInt `json:"amount"`
+	BoundaryPt int              `json:"boundaryPt"`
+}
+
+// Result is the on-chain result of a recorded swap
+type Result struct {
+	AmountX      *snapshot.BigInt `json:"amountX"`
+	AmountY      *snapshot.BigInt `json:"amountY"`
+	CurrentPoint int              `json:"currentPoint"`
+	Liquidity    *snapshot.BigInt `json:"liquidity,omitempty"`
+	LiquidityX   *snapshot.BigInt `json:"liquidityX"`
+}
+
+// Source is the origin of a vector
+type Source struct {
+	Chain string `json:"chain,omitempty"`
+	Block uint64 `json:"block,omitempty"`
+	Tx    string `json:"tx,omitempty"`
+	// origin of a vector not recorded on chain
+	Synthetic string `json:"synthetic,omitempty"`
+}
+
+// OnChain reports whether the vector is recorded from a transaction
+func (source Source) OnChain() bool {
+	return source.Synthetic == ""
+}
+
+func (source Source) check() error {
+	if !source.OnChain() {
+		if source.Chain != "" || source.Block != 0 || source.Tx != "" {
+			return fmt.Errorf("synthetic source with chain, block or tx")
+		}
+		return nil
+	}
+	tx := strings.TrimPrefix(source.Tx, "0x")
+	if _, err := hex.DecodeString(tx); err != nil || len(tx) != 64 || source.Chain == "" || source.Block == 0 {
+		return fmt.Errorf("source requires chain, block and tx hash, or synthetic")
+	}
+	return nil
+}
+
+// Vector is a recorded swap together with the pool state before it
+type Vector struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Source      Source            `json:"source"`
+	Pool        snapshot.Snapshot `json:"pool"`
+	Swap        Call              `json:"swap"`
+	Expect      Result            `json:"expect"`
+}
+
+// ReadFile reads a vector from json file,
+// name of the vector defaults to the file name
+func ReadFile(path string) (*Vector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var vector Vector
+	if err := json.Unmarshal(data, &vector); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if vector.Name == "" {
+		vector.Name = filepath.Base(path)
+	}
+	if vector.Swap.Amount == nil || vector.Expect.AmountX == nil ||
+		vector.Expect.AmountY == nil || vector.Expect.LiquidityX == nil {
+		return nil, fmt.Errorf("%s: swap.amount, expect.amountX, expect.amountY and expect.liquidityX are required", path)
+	}
+	if err := vector.Source.check(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &vector, nil
+}
+
+// LoadDir reads all *.json vectors in dir, sorted by file name
+func LoadDir(dir string) ([]*Vector, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	vectors := make([]*Vector, 0, len(paths))
+	for _, path := range paths {
+		vector, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, vector)
+	}
+	return vectors, nil
+}
@@ -7,26 +7,45 @@ import (
 	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
 )
 
+// GetAmountY returns tokenY of liquidity in [leftPt, rightPt) (given by sqrt prices)
+// like AmountMath.getAmountY, without checks of solidity integer types
 func GetAmountY(
 	liquidity *big.Int,
 	sqrtPriceL_96 *big.Int,
 	sqrtPriceR_96 *big.Int,
 	sqrtRate_96 *big.Int,
 	upper bool,
+) *big.Int {
+	return GetAmountYEVM(calc.EVM{}, liquidity, sqrtPriceL_96, sqrtPriceR_96, sqrtRate_96, upper)
+}
+
+// GetAmountYEVM is GetAmountY checked by evm like AmountMath.getAmountY
+func GetAmountYEVM(
+	evm calc.EVM,
+	liquidity *big.Int,
+	sqrtPriceL_96 *big.Int,
+	sqrtPriceR_96 *big.Int,
+	sqrtRate_96 *big.Int,
+	upper bool,
 ) *big.Int {
 	var amount *big.Int
+	evm.RequireUint128(liquidity)
 	numerator := new(big.Int).Sub(sqrtPriceR_96, sqrtPriceL_96)
 	denominator := new(big.Int).Sub(sqrtRate_96, utils.Pow96)
+	// uint160 subtraction in the contract
+	evm.RequireUint256(numerator, denominator)
 	if !upper {
 		// You should replace MulDivMath.mulDivFloor with equivalent Go function
-		amount = calc.MulDivFloor(liquidity, numerator, denominator)
+		amount = evm.MulDivFloor(liquidity, numerator, denominator)
 	} else {
 		// You should replace MulDivMath.mulDivCeil with equivalent Go function
-		amount = calc.MulDivCeil(liquidity, numerator, denominator)
+		amount = evm.MulDivCeil(liquidity, numerator, denominator)
 	}
 	return amount
 }
 
+// GetAmountX returns tokenX of liquidity in [leftPt, rightPt)
+// like AmountMath.getAmountX, without checks of solidity integer types
 func GetAmountX(
 	liquidity *big.Int,
 	leftPt int,
@@ -34,22 +53,40 @@ func GetAmountX(
 	sqrtPriceR_96 *big.Int,
 	sqrtRate_96 *big.Int,
 	upper bool,
+) *big.Int {
+	return GetAmountXEVM(calc.EVM{}, liquidity, leftPt, rightPt, sqrtPriceR_96, sqrtRate_96, upper)
+}
+
+// GetAmountXEVM is GetAmountX checked by evm like AmountMath.getAmountX
+func GetAmountXEVM(
+	evm calc.EVM,
+	liquidity *big.Int,
+	leftPt int,
+	rightPt int,
+	sqrtPriceR_96 *big.Int,
+	sqrtRate_96 *big.Int,
+	upper bool,
 ) *big.Int {
 	var amount *big.Int
+	evm.RequireUint128(liquidity)
 	// You should replace LogPowMath.getSqrtPrice with equivalent Go function
-	sqrtPricePrPl_96, _ := calc.GetSqrtPrice(rightPt - leftPt)
+	sqrtPricePrPl_96, err := calc.GetSqrtPrice(rightPt - leftPt)
+	if err != nil {
+		evm.Require(false, err.Error())
+	}
 
 	temp := new(big.Int).Mul(sqrtPriceR_96, utils.Pow96)
 	sqrtPricePrM1_96 := new(big.Int).Div(temp, sqrtRate_96)
 
 	numerator := new(big.Int).Sub(sqrtPricePrPl_96, utils.Pow96)
 	denominator := new(big.Int).Sub(sqrtPriceR_96, sqrtPricePrM1_96)
+	evm.RequireUint256(numerator, denominator)
 	if !upper {
 		// You should replace MulDivMath.mulDivFloor with equivalent Go function
-		amount = calc.MulDivFloor(liquidity, numerator, denominator)
+		amount = evm.MulDivFloor(liquidity, numerator, denominator)
 	} else {
 		// You should replace MulDivMath.mulDivCeil with equivalent Go function
-		amount = calc.MulDivCeil(liquidity, numerator, denominator)
+		amount = evm.MulDivCeil(liquidity, numerator, denominator)
 	}
 	return amount
 }
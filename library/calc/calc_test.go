@@ -0,0 +1,55 @@
+package calc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func FuzzSqrtPriceLog(f *testing.F) {
+	for _, point := range []int{MIN_POINT + 1, -799999, -1, 0, 1, 10023, 799999, MAX_POINT - 1} {
+		f.Add(point)
+	}
+	f.Fuzz(func(t *testing.T, point int) {
+		// GetLogSqrtPriceFloor accepts sqrt price in (MIN_SQRT_PRICE, MAX_SQRT_PRICE)
+		if point <= MIN_POINT || point >= MAX_POINT {
+			return
+		}
+		sqrtPrice_96, err := GetSqrtPrice(point)
+		if err != nil {
+			t.Fatalf("sqrt price of %d failed: %v", point, err)
+		}
+		logPoint, err := GetLogSqrtPriceFloor(sqrtPrice_96)
+		if err != nil {
+			t.Fatalf("log of sqrt price %s failed: %v", sqrtPrice_96, err)
+		}
+		if logPoint != point {
+			t.Fatalf("log of sqrt price not equal (%d, %d)", logPoint, point)
+		}
+		// sqrt price is strictly increasing
+		nextSqrtPrice_96, _ := GetSqrtPrice(point + 1)
+		if nextSqrtPrice_96.Cmp(sqrtPrice_96) <= 0 {
+			t.Fatalf("sqrt price of %d not greater than %d", point+1, point)
+		}
+	})
+}
+
+func FuzzMulDiv(f *testing.F) {
+	f.Add(uint64(1), uint64(1), uint64(1))
+	f.Add(uint64(7), uint64(3), uint64(2))
+	f.Add(uint64(1<<63), uint64(1<<63), uint64(3))
+	f.Fuzz(func(t *testing.T, a, b, c uint64) {
+		if c == 0 {
+			return
+		}
+		x, y, z := new(big.Int).SetUint64(a), new(big.Int).SetUint64(b), new(big.Int).SetUint64(c)
+		floor := MulDivFloor(x, y, z)
+		ceil := MulDivCeil(x, y, z)
+		mul := new(big.Int).Mul(x, y)
+		if new(big.Int).Mul(floor, z).Cmp(mul) > 0 || new(big.Int).Mul(ceil, z).Cmp(mul) < 0 {
+			t.Fatalf("mulDiv(%d, %d, %d) out of bound: floor %s ceil %s", a, b, c, floor, ceil)
+		}
+		if diff := new(big.Int).Sub(ceil, floor); diff.Sign() < 0 || diff.Cmp(big.NewInt(1)) > 0 {
+			t.Fatalf("mulDiv(%d, %d, %d): floor %s ceil %s", a, b, c, floor, ceil)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package calc
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// panic codes of solidity >= 0.8
+const (
+	PanicArithmetic     = 0x11
+	PanicDivisionByZero = 0x12
+)
+
+var (
+	MaxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	MaxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	MaxInt128  = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	MinInt128  = new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+)
+
+// Revert is the error of a call the contract would revert on,
+// Reason is the revert string of require(),
+// Code is the panic code if the contract reverts with Panic(uint256)
+type Revert struct {
+	Reason string
+	Code   int
+}
+
+func (err *Revert) Error() string {
+	if err.Code != 0 {
+		return fmt.Sprintf("execution reverted: panic 0x%02x", err.Code)
+	}
+	if err.Reason == "" {
+		return "execution reverted"
+	}
+	return "execution reverted: " + err.Reason
+}
+
+// EVM is the mode of a calculation, with Strict it checks uint128 / uint256 / int128
+// bounds like the contracts and panics with *Revert where they revert, functions
+// returning error recover it by RecoverRevert. the zero value checks nothing,
+// as the package functions MulDivFloor and MulDivCeil
+type EVM struct {
+	Strict bool
+}
+
+// Require reverts with reason if cond is false in strict mode
+func (evm EVM) Require(cond bool, reason string) {
+	if !cond && evm.Strict {
+		panic(&Revert{Reason: reason})
+	}
+}
+
+func (evm EVM) requireRange(min, max *big.Int, values []*big.Int) {
+	if !evm.Strict {
+		return
+	}
+	for _, value := range values {
+		if value.Cmp(min) < 0 || value.Cmp(max) > 0 {
+			panic(&Revert{Code: PanicArithmetic})
+		}
+	}
+}
+
+// RequireUint128 reverts with arithmetic panic if any value is out of uint128 in strict mode
+func (evm EVM) RequireUint128(values ...*big.Int) {
+	evm.requireRange(big.NewInt(0), MaxUint128, values)
+}
+
+// RequireUint256 reverts with arithmetic panic if any value is out of uint256 in strict mode
+func (evm EVM) RequireUint256(values ...*big.Int) {
+	evm.requireRange(big.NewInt(0), MaxUint256, values)
+}
+
+// RequireInt128 reverts with arithmetic panic if any value is out of int128 in strict mode
+func (evm EVM) RequireInt128(values ...*big.Int) {
+	evm.requireRange(MinInt128, MaxInt128, values)
+}
+
+// AddDelta returns x + delta as LiquidityMath.addDelta,
+// where x is uint128 liquidity and delta is int128 liquidity delta
+func (evm EVM) AddDelta(x, delta *big.Int) *big.Int {
+	evm.RequireUint128(x)
+	evm.RequireInt128(delta)
+	z := new(big.Int).Add(x, delta)
+	if delta.Sign() < 0 {
+		evm.Require(z.Sign() >= 0, "LS")
+	} else {
+		evm.Require(z.Cmp(MaxUint128) <= 0, "LA")
+	}
+	return z
+}
+
+// RecoverRevert is deferred by functions returning error,
+// it stores a *Revert raised in strict mode into err,
+// other panics are raised again
+func RecoverRevert(err *error) {
+	if r := recover(); r != nil {
+		revert, ok := r.(*Revert)
+		if !ok {
+			panic(r)
+		}
+		*err = revert
+	}
+}
@@ -22,3 +22,10 @@ func MinBigInt(x, y *big.Int) *big.Int {
 	}
 	return new(big.Int).Set(y)
 }
+
+func MaxBigInt(x, y *big.Int) *big.Int {
+	if x.Cmp(y) >= 0 {
+		return new(big.Int).Set(x)
+	}
+	return new(big.Int).Set(y)
+}
@@ -4,13 +4,38 @@ import "math/big"
 
 // MulDivFloor performs multiplication first and then division, flooring the result.
 func MulDivFloor(a, b, c *big.Int) *big.Int {
-	mul := new(big.Int).Mul(a, b)
-	return new(big.Int).Div(mul, c)
+	return EVM{}.MulDivFloor(a, b, c)
 }
 
 // MulDivCeil performs multiplication first and then division, ceiling the result.
 func MulDivCeil(a, b, c *big.Int) *big.Int {
+	return EVM{}.MulDivCeil(a, b, c)
+}
+
+// MulDivFloor is MulDivFloor checked as MulDivMath.mulDivFloor in strict mode
+func (evm EVM) MulDivFloor(a, b, c *big.Int) *big.Int {
+	evm.requireMulDiv(a, b, c)
+	mul := new(big.Int).Mul(a, b)
+	res := new(big.Int).Div(mul, c)
+	// MulDivMath reverts without reason if result overflows uint256
+	evm.Require(res.Cmp(MaxUint256) <= 0, "")
+	return res
+}
+
+// MulDivCeil is MulDivCeil checked as MulDivMath.mulDivCeil in strict mode
+func (evm EVM) MulDivCeil(a, b, c *big.Int) *big.Int {
+	evm.requireMulDiv(a, b, c)
 	mul := new(big.Int).Mul(a, b)
 	sum := new(big.Int).Add(mul, new(big.Int).Sub(c, big.NewInt(1)))
-	return new(big.Int).Div(sum, c)
+	res := new(big.Int).Div(sum, c)
+	evm.Require(res.Cmp(MaxUint256) <= 0, "")
+	return res
+}
+
+// requireMulDiv checks operands of MulDivMath in strict mode
+func (evm EVM) requireMulDiv(a, b, c *big.Int) {
+	evm.RequireUint256(a, b, c)
+	if c.Sign() == 0 && evm.Strict {
+		panic(&Revert{Code: PanicDivisionByZero})
+	}
 }
@@ -0,0 +1,109 @@
+package swapmath
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+)
+
+// rangeState returns a valid state from fuzz inputs
+func rangeState(point int32, liquidity, liquidityX uint64) utils.State {
+	currentPoint := int(point) % 700000
+	sqrtPrice_96, _ := calc.GetSqrtPrice(currentPoint)
+	l := new(big.Int).SetUint64(liquidity)
+	lx := new(big.Int).SetUint64(liquidityX)
+	if liquidity > 0 {
+		lx.Mod(lx, new(big.Int).Add(l, big.NewInt(1)))
+	} else {
+		lx.SetInt64(0)
+	}
+	return utils.State{LiquidityX: lx, Liquidity: l, CurrentPoint: currentPoint, SqrtPrice_96: sqrtPrice_96}
+}
+
+func FuzzX2YRange(f *testing.F) {
+	f.Add(int32(1887), uint64(700000), uint64(246660), uint16(100), uint64(1000000000))
+	f.Add(int32(-6215), uint64(1), uint64(0), uint16(1), uint64(1))
+	f.Fuzz(func(t *testing.T, point int32, liquidity, liquidityX uint64, width uint16, amount uint64) {
+		if liquidity == 0 || amount == 0 {
+			return
+		}
+		st := rangeState(point, liquidity, liquidityX)
+		leftPt := st.CurrentPoint - int(width)
+		sqrtRate_96, _ := calc.GetSqrtPrice(1)
+		amountX := new(big.Int).SetUint64(amount)
+		ret := X2YRange(st, leftPt, sqrtRate_96, new(big.Int).Set(amountX))
+		if ret.CostX.Cmp(amountX) > 0 {
+			t.Fatalf("costX %s more than amountX %s", ret.CostX, amountX)
+		}
+		if ret.FinalPt < leftPt || ret.FinalPt > st.CurrentPoint {
+			t.Fatalf("final point %d not in [%d, %d]", ret.FinalPt, leftPt, st.CurrentPoint)
+		}
+		if ret.LiquidityX.Sign() < 0 || ret.LiquidityX.Cmp(st.Liquidity) > 0 {
+			t.Fatalf("liquidityX %s not in [0, %s]", ret.LiquidityX, st.Liquidity)
+		}
+	})
+}
+
+func FuzzY2XRange(f *testing.F) {
+	f.Add(int32(-6215), uint64(200000), uint64(31891), uint16(100), uint64(1000000000))
+	f.Add(int32(1887), uint64(1), uint64(1), uint16(1), uint64(1))
+	f.Fuzz(func(t *testing.T, point int32, liquidity, liquidityX uint64, width uint16, amount uint64) {
+		if liquidity == 0 || amount == 0 || width == 0 {
+			return
+		}
+		st := rangeState(point, liquidity, liquidityX)
+		rightPt := st.CurrentPoint + int(width)
+		sqrtRate_96, _ := calc.GetSqrtPrice(1)
+		amountY := new(big.Int).SetUint64(amount)
+		ret := Y2XRange(st, rightPt, sqrtRate_96, new(big.Int).Set(amountY))
+		if ret.CostY.Cmp(amountY) > 0 {
+			t.Fatalf("costY %s more than amountY %s", ret.CostY, amountY)
+		}
+		if ret.FinalPt < st.CurrentPoint || ret.FinalPt > rightPt {
+			t.Fatalf("final point %d not in [%d, %d]", ret.FinalPt, st.CurrentPoint, rightPt)
+		}
+		if ret.LiquidityX.Sign() < 0 || ret.LiquidityX.Cmp(st.Liquidity) > 0 {
+			t.Fatalf("liquidityX %s not in [0, %s]", ret.LiquidityX, st.Liquidity)
+		}
+	})
+}
+
+func FuzzAtPrice(f *testing.F) {
+	f.Add(int32(1887), uint64(1000000000), uint64(120000000000))
+	f.Add(int32(-6200), uint64(1), uint64(1))
+	f.Fuzz(func(t *testing.T, point int32, amount, curr uint64) {
+		sqrtPrice_96, _ := calc.GetSqrtPrice(int(point) % 700000)
+		amountIn, currOut := new(big.Int).SetUint64(amount), new(big.Int).SetUint64(curr)
+		costX, acquireY := X2YAtPrice(amountIn, sqrtPrice_96, currOut)
+		if costX.Cmp(amountIn) > 0 || acquireY.Cmp(currOut) > 0 {
+			t.Fatalf("x2y at price: cost %s of %s, acquire %s of %s", costX, amountIn, acquireY, currOut)
+		}
+		costY, acquireX := Y2XAtPrice(amountIn, sqrtPrice_96, currOut)
+		if costY.Cmp(amountIn) > 0 || acquireX.Cmp(currOut) > 0 {
+			t.Fatalf("y2x at price: cost %s of %s, acquire %s of %s", costY, amountIn, acquireX, currOut)
+		}
+	})
+}
+
+func TestRangeEVM(t *testing.T) {
+	st := rangeState(0, 1000000, 0)
+	sqrtRate_96, _ := calc.GetSqrtPrice(1)
+	amountX := new(big.Int).Lsh(big.NewInt(1), 128)
+	expect := X2YRange(st, -400, sqrtRate_96, new(big.Int).Set(amountX))
+	if ret := X2YRangeEVM(calc.EVM{}, st, -400, sqrtRate_96, new(big.Int).Set(amountX)); ret.CostX.Cmp(expect.CostX) != 0 {
+		t.Fatalf("costX %s, expect %s", ret.CostX, expect.CostX)
+	}
+
+	var err error
+	func() {
+		defer calc.RecoverRevert(&err)
+		X2YRangeEVM(calc.EVM{Strict: true}, st, -400, sqrtRate_96, amountX)
+	}()
+	var revert *calc.Revert
+	if !errors.As(err, &revert) || revert.Code != calc.PanicArithmetic {
+		t.Fatalf("expect arithmetic panic for amountX over uint128, got %v", err)
+	}
+}
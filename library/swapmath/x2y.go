@@ -23,16 +23,25 @@ type X2YRangeRetState struct {
 	LiquidityX *big.Int
 }
 
+// X2YAtPrice swaps amountX for at most currY at a single price,
+// without checks of solidity integer types
 func X2YAtPrice(amountX, sqrtPrice_96, currY *big.Int) (costX, acquireY *big.Int) {
+	return X2YAtPriceEVM(calc.EVM{}, amountX, sqrtPrice_96, currY)
+}
+
+// X2YAtPriceEVM is X2YAtPrice checked by evm
+func X2YAtPriceEVM(evm calc.EVM, amountX, sqrtPrice_96, currY *big.Int) (costX, acquireY *big.Int) {
+	evm.RequireUint128(amountX, currY)
 
-	l := calc.MulDivFloor(amountX, sqrtPrice_96, utils.Pow96)
-	acquireY = calc.MulDivFloor(l, sqrtPrice_96, utils.Pow96)
+	l := evm.MulDivFloor(amountX, sqrtPrice_96, utils.Pow96)
+	acquireY = evm.MulDivFloor(l, sqrtPrice_96, utils.Pow96)
 	if acquireY.Cmp(currY) > 0 {
 		acquireY.Set(currY)
 	}
-	l = calc.MulDivCeil(acquireY, utils.Pow96, sqrtPrice_96)
-	costX = calc.MulDivCeil(l, utils.Pow96, sqrtPrice_96)
+	l = evm.MulDivCeil(acquireY, utils.Pow96, sqrtPrice_96)
+	costX = evm.MulDivCeil(l, utils.Pow96, sqrtPrice_96)
 
+	evm.RequireUint128(costX, acquireY)
 	return costX, acquireY
 }
 
@@ -42,16 +51,16 @@ type X2YAtPriceLiquidityResult struct {
 	NewLiquidityX *big.Int
 }
 
-func x2YAtPriceLiquidity(amountX, sqrtPrice_96, liquidity, liquidityX *big.Int) X2YAtPriceLiquidityResult {
+func x2YAtPriceLiquidity(evm calc.EVM, amountX, sqrtPrice_96, liquidity, liquidityX *big.Int) X2YAtPriceLiquidityResult {
 	var costX, acquireY, newLiquidityX *big.Int
 	var maxTransformLiquidityX, transformLiquidityX *big.Int
 
 	liquidityY := new(big.Int).Sub(liquidity, liquidityX)
-	maxTransformLiquidityX = calc.MulDivFloor(amountX, sqrtPrice_96, utils.Pow96)
+	maxTransformLiquidityX = evm.MulDivFloor(amountX, sqrtPrice_96, utils.Pow96)
 	transformLiquidityX = calc.MinBigInt(maxTransformLiquidityX, liquidityY)
 
-	costX = calc.MulDivCeil(transformLiquidityX, utils.Pow96, sqrtPrice_96)
-	acquireY = calc.MulDivFloor(transformLiquidityX, sqrtPrice_96, utils.Pow96)
+	costX = evm.MulDivCeil(transformLiquidityX, utils.Pow96, sqrtPrice_96)
+	acquireY = evm.MulDivFloor(transformLiquidityX, sqrtPrice_96, utils.Pow96)
 	newLiquidityX = new(big.Int).Add(liquidityX, transformLiquidityX)
 
 	return X2YAtPriceLiquidityResult{CostX: costX, AcquireY: acquireY, NewLiquidityX: newLiquidityX}
@@ -85,15 +94,15 @@ type RangeX2Y struct {
 	SqrtRate_96 *big.Int
 }
 
-func x2YRangeComplete(rg RangeX2Y, amountX *big.Int) X2YRangeCompRet {
+func x2YRangeComplete(evm calc.EVM, rg RangeX2Y, amountX *big.Int) X2YRangeCompRet {
 	var ret X2YRangeCompRet
-	sqrtPricePrM1_96 := calc.MulDivCeil(rg.SqrtPriceR_96, utils.Pow96, rg.SqrtRate_96)
+	sqrtPricePrM1_96 := evm.MulDivCeil(rg.SqrtPriceR_96, utils.Pow96, rg.SqrtRate_96)
 	sqrtPricePrMl_96, _ := calc.GetSqrtPrice(rg.RightPt - rg.LeftPt)
-	maxX := calc.MulDivCeil(rg.Liquidity, new(big.Int).Sub(sqrtPricePrMl_96, utils.Pow96), new(big.Int).Sub(rg.SqrtPriceR_96, sqrtPricePrM1_96))
+	maxX := evm.MulDivCeil(rg.Liquidity, new(big.Int).Sub(sqrtPricePrMl_96, utils.Pow96), new(big.Int).Sub(rg.SqrtPriceR_96, sqrtPricePrM1_96))
 
 	if maxX.Cmp(amountX) <= 0 {
 		ret.CostX = maxX
-		ret.AcquireY = amountmath.GetAmountY(rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
+		ret.AcquireY = amountmath.GetAmountYEVM(evm, rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
 		ret.CompleteLiquidity = true
 	} else {
 		sqrtValue_96 := new(big.Int).Add(
@@ -121,7 +130,7 @@ func x2YRangeComplete(rg RangeX2Y, amountX *big.Int) X2YRangeCompRet {
 			ret.SqrtLoc_96, _ = calc.GetSqrtPrice(ret.LocPt)
 		} else {
 			sqrtPricePrMloc_96, _ := calc.GetSqrtPrice(rg.RightPt - ret.LocPt)
-			costX256 := calc.MulDivCeil(rg.Liquidity, new(big.Int).Sub(sqrtPricePrMloc_96, utils.Pow96), new(big.Int).Sub(rg.SqrtPriceR_96, sqrtPricePrM1_96))
+			costX256 := evm.MulDivCeil(rg.Liquidity, new(big.Int).Sub(sqrtPricePrMloc_96, utils.Pow96), new(big.Int).Sub(rg.SqrtPriceR_96, sqrtPricePrM1_96))
 			ret.CostX = calc.MinBigInt(costX256, amountX)
 			ret.LocPt = ret.LocPt - 1
 			ret.SqrtLoc_96, _ = calc.GetSqrtPrice(ret.LocPt)
@@ -135,14 +144,29 @@ func x2YRangeComplete(rg RangeX2Y, amountX *big.Int) X2YRangeCompRet {
 					utils.Pow96,
 				),
 			)
-			ret.AcquireY = amountmath.GetAmountY(rg.Liquidity, sqrtLocA1_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
+			ret.AcquireY = amountmath.GetAmountYEVM(evm, rg.Liquidity, sqrtLocA1_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
 		}
 	}
 
 	return ret
 }
 
+// X2YRange swaps in range of [currentState.CurrentPoint, leftPt],
+// without checks of solidity integer types
 func X2YRange(currentState utils.State, leftPt int, sqrtRate_96 *big.Int, amountX *big.Int) X2YRangeRetState {
+	return X2YRangeEVM(calc.EVM{}, currentState, leftPt, sqrtRate_96, amountX)
+}
+
+// X2YRangeEVM is X2YRange checked by evm,
+// with a strict evm it reverts where the contract would overflow uint128
+func X2YRangeEVM(evm calc.EVM, currentState utils.State, leftPt int, sqrtRate_96 *big.Int, amountX *big.Int) X2YRangeRetState {
+	evm.RequireUint128(currentState.Liquidity, currentState.LiquidityX, amountX)
+	retState := x2YRange(evm, currentState, leftPt, sqrtRate_96, amountX)
+	evm.RequireUint128(retState.CostX, retState.AcquireY)
+	return retState
+}
+
+func x2YRange(evm calc.EVM, currentState utils.State, leftPt int, sqrtRate_96 *big.Int, amountX *big.Int) X2YRangeRetState {
 	var retState X2YRangeRetState
 	retState.CostX = big.NewInt(0)
 	retState.AcquireY = big.NewInt(0)
@@ -151,7 +175,7 @@ func X2YRange(currentState utils.State, leftPt int, sqrtRate_96 *big.Int, amount
 
 	currentHasY := currentState.LiquidityX.Cmp(currentState.Liquidity) < 0
 	if currentHasY && (currentState.LiquidityX.Cmp(new(big.Int).SetInt64(0)) > 0 || leftPt == currentState.CurrentPoint) {
-		ret := x2YAtPriceLiquidity(amountX, currentState.SqrtPrice_96, currentState.Liquidity, currentState.LiquidityX)
+		ret := x2YAtPriceLiquidity(evm, amountX, currentState.SqrtPrice_96, currentState.Liquidity, currentState.LiquidityX)
 		retState.CostX = ret.CostX
 		retState.AcquireY = ret.AcquireY
 		retState.LiquidityX = ret.NewLiquidityX
@@ -185,6 +209,7 @@ func X2YRange(currentState utils.State, leftPt int, sqrtRate_96 *big.Int, amount
 	if leftPt < currentState.CurrentPoint {
 		sqrtPriceL_96, _ := calc.GetSqrtPrice(leftPt)
 		ret := x2YRangeComplete(
+			evm,
 			RangeX2Y{
 				Liquidity:     currentState.Liquidity,
 				SqrtPriceL_96: sqrtPriceL_96,
@@ -204,7 +229,7 @@ func X2YRange(currentState utils.State, leftPt int, sqrtRate_96 *big.Int, amount
 			retState.SqrtFinalPrice_96 = sqrtPriceL_96
 			retState.LiquidityX = currentState.Liquidity
 		} else {
-			locRet := x2YAtPriceLiquidity(amountX, ret.SqrtLoc_96, currentState.Liquidity, big.NewInt(0))
+			locRet := x2YAtPriceLiquidity(evm, amountX, ret.SqrtLoc_96, currentState.Liquidity, big.NewInt(0))
 			locCostX := locRet.CostX
 			locAcquireY := locRet.AcquireY
 			retState.LiquidityX = locRet.NewLiquidityX
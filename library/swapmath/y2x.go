@@ -24,12 +24,21 @@ type Y2XRangeRetState struct {
 	LiquidityX *big.Int
 }
 
+// Y2XAtPrice swaps amountY for at most currX at a single price,
+// without checks of solidity integer types
 func Y2XAtPrice(amountY *big.Int, sqrtPrice_96 *big.Int, currX *big.Int) (costY, acquireX *big.Int) {
-	l := calc.MulDivFloor(amountY, utils.Pow96, sqrtPrice_96)
+	return Y2XAtPriceEVM(calc.EVM{}, amountY, sqrtPrice_96, currX)
+}
+
+// Y2XAtPriceEVM is Y2XAtPrice checked by evm
+func Y2XAtPriceEVM(evm calc.EVM, amountY *big.Int, sqrtPrice_96 *big.Int, currX *big.Int) (costY, acquireX *big.Int) {
+	evm.RequireUint128(amountY, currX)
+	l := evm.MulDivFloor(amountY, utils.Pow96, sqrtPrice_96)
 	// acquireX <= currX <= uint128.max
-	acquireX = calc.MinBigInt(calc.MulDivFloor(l, utils.Pow96, sqrtPrice_96), currX)
-	l = calc.MulDivCeil(acquireX, sqrtPrice_96, utils.Pow96)
-	costY = calc.MulDivCeil(l, sqrtPrice_96, utils.Pow96)
+	acquireX = calc.MinBigInt(evm.MulDivFloor(l, utils.Pow96, sqrtPrice_96), currX)
+	l = evm.MulDivCeil(acquireX, sqrtPrice_96, utils.Pow96)
+	costY = evm.MulDivCeil(l, sqrtPrice_96, utils.Pow96)
+	evm.RequireUint128(costY, acquireX)
 	return costY, acquireX
 }
 
@@ -39,14 +48,14 @@ type Y2XAtPriceLiquidityResult struct {
 	NewLiquidityX *big.Int
 }
 
-func y2XAtPriceLiquidity(amountY *big.Int, sqrtPrice_96 *big.Int, liquidityX *big.Int) Y2XAtPriceLiquidityResult {
+func y2XAtPriceLiquidity(evm calc.EVM, amountY *big.Int, sqrtPrice_96 *big.Int, liquidityX *big.Int) Y2XAtPriceLiquidityResult {
 	// amountY * TwoPower.Pow96 < 2^128 * 2^96 = 2^224 < 2^256
 	maxTransformLiquidityY := new(big.Int).Mul(amountY, utils.Pow96)
 	maxTransformLiquidityY.Div(maxTransformLiquidityY, sqrtPrice_96)
 	// transformLiquidityY <= liquidityX
 	transformLiquidityY := calc.MinBigInt(maxTransformLiquidityY, liquidityX)
 	// costY <= amountY
-	costY := calc.MulDivCeil(transformLiquidityY, sqrtPrice_96, utils.Pow96)
+	costY := evm.MulDivCeil(transformLiquidityY, sqrtPrice_96, utils.Pow96)
 	// transformLiquidityY * 2^96 < 2^224 < 2^256
 	acquireX := new(big.Int).Mul(transformLiquidityY, utils.Pow96)
 	acquireX.Div(acquireX, sqrtPrice_96)
@@ -71,19 +80,19 @@ type Y2XRangeCompRet struct {
 	SqrtLoc_96        *big.Int
 }
 
-func y2XRangeComplete(rg RangeY2X, amountY *big.Int) Y2XRangeCompRet {
+func y2XRangeComplete(evm calc.EVM, rg RangeY2X, amountY *big.Int) Y2XRangeCompRet {
 	ret := Y2XRangeCompRet{}
-	maxY := amountmath.GetAmountY(rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, true)
+	maxY := amountmath.GetAmountYEVM(evm, rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, true)
 	if maxY.Cmp(amountY) <= 0 {
 		// ret.costY <= maxY <= uint128.max
 		ret.CostY = maxY
-		ret.AcquireX = amountmath.GetAmountX(rg.Liquidity, rg.LeftPt, rg.RightPt, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
+		ret.AcquireX = amountmath.GetAmountXEVM(evm, rg.Liquidity, rg.LeftPt, rg.RightPt, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
 		// we complete this liquidity segment
 		ret.CompleteLiquidity = true
 	} else {
 		// we should locate highest price
 		// uint160 is enough for muldiv and adding, because amountY < maxY
-		sqrtLoc_96 := calc.MulDivFloor(amountY, new(big.Int).Sub(rg.SqrtRate_96, utils.Pow96), rg.Liquidity)
+		sqrtLoc_96 := evm.MulDivFloor(amountY, new(big.Int).Sub(rg.SqrtRate_96, utils.Pow96), rg.Liquidity)
 		sqrtLoc_96.Add(sqrtLoc_96, rg.SqrtPriceL_96)
 		ret.LocPt, _ = calc.GetLogSqrtPriceFloor(sqrtLoc_96)
 
@@ -98,19 +107,34 @@ func y2XRangeComplete(rg RangeY2X, amountY *big.Int) Y2XRangeCompRet {
 			return ret
 		}
 
-		costY256 := amountmath.GetAmountY(rg.Liquidity, rg.SqrtPriceL_96, ret.SqrtLoc_96, rg.SqrtRate_96, true)
+		costY256 := amountmath.GetAmountYEVM(evm, rg.Liquidity, rg.SqrtPriceL_96, ret.SqrtLoc_96, rg.SqrtRate_96, true)
 		// ret.costY <= amountY <= uint128.max
 		ret.CostY = calc.MinBigInt(costY256, amountY)
 
 		// costY <= amountY even if the costY is the upperbound of the result
 		// because amountY is not a real and sqrtLoc_96 <= sqrtLoc256_96
-		ret.AcquireX = amountmath.GetAmountX(rg.Liquidity, rg.LeftPt, ret.LocPt, ret.SqrtLoc_96, rg.SqrtRate_96, false)
+		ret.AcquireX = amountmath.GetAmountXEVM(evm, rg.Liquidity, rg.LeftPt, ret.LocPt, ret.SqrtLoc_96, rg.SqrtRate_96, false)
 
 	}
 	return ret
 }
 
+// Y2XRange swaps in range of [currentState.CurrentPoint, rightPt],
+// without checks of solidity integer types
 func Y2XRange(currentState utils.State, rightPt int, sqrtRate_96 *big.Int, amountY *big.Int) Y2XRangeRetState {
+	return Y2XRangeEVM(calc.EVM{}, currentState, rightPt, sqrtRate_96, amountY)
+}
+
+// Y2XRangeEVM is Y2XRange checked by evm,
+// with a strict evm it reverts where the contract would overflow uint128
+func Y2XRangeEVM(evm calc.EVM, currentState utils.State, rightPt int, sqrtRate_96 *big.Int, amountY *big.Int) Y2XRangeRetState {
+	evm.RequireUint128(currentState.Liquidity, currentState.LiquidityX, amountY)
+	retState := y2XRange(evm, currentState, rightPt, sqrtRate_96, amountY)
+	evm.RequireUint128(retState.CostY, retState.AcquireX)
+	return retState
+}
+
+func y2XRange(evm calc.EVM, currentState utils.State, rightPt int, sqrtRate_96 *big.Int, amountY *big.Int) Y2XRangeRetState {
 	retState := Y2XRangeRetState{
 		CostY:      big.NewInt(0),
 		AcquireX:   big.NewInt(0),
@@ -121,7 +145,7 @@ func Y2XRange(currentState utils.State, rightPt int, sqrtRate_96 *big.Int, amoun
 	// first, if current point is not all x, we can not move right directly
 	startHasY := currentState.LiquidityX.Cmp(currentState.Liquidity) < 0
 	if startHasY {
-		ret := y2XAtPriceLiquidity(amountY, currentState.SqrtPrice_96, currentState.LiquidityX)
+		ret := y2XAtPriceLiquidity(evm, amountY, currentState.SqrtPrice_96, currentState.LiquidityX)
 		retState.LiquidityX = ret.NewLiquidityX
 		retState.CostY = ret.CostY
 		retState.AcquireX = ret.AcquireX
@@ -149,6 +173,7 @@ func Y2XRange(currentState utils.State, rightPt int, sqrtRate_96 *big.Int, amoun
 
 	// (uint128 liquidCostY, uint256 liquidAcquireX, bool liquidComplete, int24 locPt, uint160 sqrtLoc_96)
 	ret := y2XRangeComplete(
+		evm,
 		RangeY2X{
 			Liquidity:     currentState.Liquidity,
 			SqrtPriceL_96: currentState.SqrtPrice_96,
@@ -170,7 +195,7 @@ func Y2XRange(currentState utils.State, rightPt int, sqrtRate_96 *big.Int, amoun
 	} else {
 
 		//locCostY, locAcquireX, retState.LiquidityX =
-		locRet := y2XAtPriceLiquidity(amountY, ret.SqrtLoc_96, currentState.Liquidity)
+		locRet := y2XAtPriceLiquidity(evm, amountY, ret.SqrtLoc_96, currentState.Liquidity)
 		locCostY := locRet.CostY
 		locAcquireX := locRet.AcquireX
 		retState.LiquidityX = locRet.NewLiquidityX
@@ -0,0 +1,83 @@
+package swapmathdesire
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+)
+
+// rangeState returns a valid state from fuzz inputs
+func rangeState(point int32, liquidity, liquidityX uint64) utils.State {
+	currentPoint := int(point) % 700000
+	sqrtPrice_96, _ := calc.GetSqrtPrice(currentPoint)
+	l := new(big.Int).SetUint64(liquidity)
+	lx := new(big.Int).SetUint64(liquidityX)
+	lx.Mod(lx, new(big.Int).Add(l, big.NewInt(1)))
+	return utils.State{LiquidityX: lx, Liquidity: l, CurrentPoint: currentPoint, SqrtPrice_96: sqrtPrice_96}
+}
+
+func FuzzX2YRange(f *testing.F) {
+	f.Add(int32(1729), uint64(500000), uint64(134333), uint16(100), uint64(1000000000))
+	f.Add(int32(-6215), uint64(1), uint64(0), uint16(1), uint64(1))
+	f.Fuzz(func(t *testing.T, point int32, liquidity, liquidityX uint64, width uint16, desire uint64) {
+		if liquidity == 0 || desire == 0 {
+			return
+		}
+		st := rangeState(point, liquidity, liquidityX)
+		leftPt := st.CurrentPoint - int(width)
+		sqrtRate_96, _ := calc.GetSqrtPrice(1)
+		ret := X2YRange(st, leftPt, sqrtRate_96, new(big.Int).SetUint64(desire))
+		if ret.FinalPt < leftPt || ret.FinalPt > st.CurrentPoint {
+			t.Fatalf("final point %d not in [%d, %d]", ret.FinalPt, leftPt, st.CurrentPoint)
+		}
+		if ret.LiquidityX.Sign() < 0 || ret.LiquidityX.Cmp(st.Liquidity) > 0 {
+			t.Fatalf("liquidityX %s not in [0, %s]", ret.LiquidityX, st.Liquidity)
+		}
+		if ret.AcquireY.Sign() > 0 && ret.CostX.Sign() <= 0 {
+			t.Fatalf("acquire %s tokenY for free", ret.AcquireY)
+		}
+	})
+}
+
+func FuzzY2XRange(f *testing.F) {
+	f.Add(int32(-6215), uint64(200000), uint64(31891), uint16(100), uint64(1000000000))
+	f.Add(int32(1887), uint64(1), uint64(1), uint16(1), uint64(1))
+	f.Fuzz(func(t *testing.T, point int32, liquidity, liquidityX uint64, width uint16, desire uint64) {
+		if liquidity == 0 || desire == 0 || width == 0 {
+			return
+		}
+		st := rangeState(point, liquidity, liquidityX)
+		rightPt := st.CurrentPoint + int(width)
+		sqrtRate_96, _ := calc.GetSqrtPrice(1)
+		ret := Y2XRange(st, rightPt, sqrtRate_96, new(big.Int).SetUint64(desire))
+		if ret.FinalPt < st.CurrentPoint || ret.FinalPt > rightPt {
+			t.Fatalf("final point %d not in [%d, %d]", ret.FinalPt, st.CurrentPoint, rightPt)
+		}
+		if ret.LiquidityX.Sign() < 0 || ret.LiquidityX.Cmp(st.Liquidity) > 0 {
+			t.Fatalf("liquidityX %s not in [0, %s]", ret.LiquidityX, st.Liquidity)
+		}
+		if ret.AcquireX.Sign() > 0 && ret.CostY.Sign() <= 0 {
+			t.Fatalf("acquire %s tokenX for free", ret.AcquireX)
+		}
+	})
+}
+
+func FuzzAtPrice(f *testing.F) {
+	f.Add(int32(1887), uint64(1000000000), uint64(120000000000))
+	f.Add(int32(-6200), uint64(1), uint64(1))
+	f.Fuzz(func(t *testing.T, point int32, desire, curr uint64) {
+		sqrtPrice_96, _ := calc.GetSqrtPrice(int(point) % 700000)
+		desireOut, currOut := new(big.Int).SetUint64(desire), new(big.Int).SetUint64(curr)
+		maxOut := calc.MinBigInt(desireOut, currOut)
+		costX, acquireY := X2YAtPrice(desireOut, sqrtPrice_96, currOut)
+		if acquireY.Cmp(maxOut) > 0 || (acquireY.Sign() > 0 && costX.Sign() <= 0) {
+			t.Fatalf("x2y at price: cost %s, acquire %s of %s", costX, acquireY, maxOut)
+		}
+		costY, acquireX := Y2XAtPrice(desireOut, sqrtPrice_96, currOut)
+		if acquireX.Cmp(maxOut) > 0 || (acquireX.Sign() > 0 && costY.Sign() <= 0) {
+			t.Fatalf("y2x at price: cost %s, acquire %s of %s", costY, acquireX, maxOut)
+		}
+	})
+}
@@ -23,13 +23,22 @@ type X2YRangeRetState struct {
 	LiquidityX *big.Int
 }
 
+// X2YAtPrice swaps for desireY (at most currY) at a single price,
+// without checks of solidity integer types
 func X2YAtPrice(desireY, sqrtPrice_96, currY *big.Int) (costX, acquireY *big.Int) {
+	return X2YAtPriceEVM(calc.EVM{}, desireY, sqrtPrice_96, currY)
+}
+
+// X2YAtPriceEVM is X2YAtPrice checked by evm
+func X2YAtPriceEVM(evm calc.EVM, desireY, sqrtPrice_96, currY *big.Int) (costX, acquireY *big.Int) {
+	evm.RequireUint128(desireY, currY)
 	acquireY = new(big.Int).Set(desireY)
 	if acquireY.Cmp(currY) == 1 {
 		acquireY = new(big.Int).Set(currY)
 	}
-	l := calc.MulDivCeil(acquireY, utils.Pow96, sqrtPrice_96)
-	costX = calc.MulDivCeil(l, utils.Pow96, sqrtPrice_96)
+	l := evm.MulDivCeil(acquireY, utils.Pow96, sqrtPrice_96)
+	costX = evm.MulDivCeil(l, utils.Pow96, sqrtPrice_96)
+	evm.RequireUint128(costX, acquireY)
 	return costX, acquireY
 }
 
@@ -39,19 +48,19 @@ type X2YAtPriceLiquidityResult struct {
 	NewLiquidityX *big.Int
 }
 
-func x2YAtPriceLiquidity(desireY, sqrtPrice_96, liquidity, liquidityX *big.Int) X2YAtPriceLiquidityResult {
+func x2YAtPriceLiquidity(evm calc.EVM, desireY, sqrtPrice_96, liquidity, liquidityX *big.Int) X2YAtPriceLiquidityResult {
 	var costX, acquireY, newLiquidityX *big.Int
 	var maxTransformLiquidityX, transformLiquidityX *big.Int
 
 	liquidityY := new(big.Int).Sub(liquidity, liquidityX)
 	// desireY * 2^96 <= 2^128 * 2^96 <= 2^224 < 2^256
-	maxTransformLiquidityX = calc.MulDivCeil(desireY, utils.Pow96, sqrtPrice_96)
+	maxTransformLiquidityX = evm.MulDivCeil(desireY, utils.Pow96, sqrtPrice_96)
 	// transformLiquidityX <= liquidityY <= uint128.max
 	transformLiquidityX = calc.MinBigInt(maxTransformLiquidityX, liquidityY)
 	// transformLiquidityX * 2^96 <= 2^128 * 2^96 <= 2^224 < 2^256
-	costX = calc.MulDivCeil(transformLiquidityX, utils.Pow96, sqrtPrice_96)
+	costX = evm.MulDivCeil(transformLiquidityX, utils.Pow96, sqrtPrice_96)
 	// acquireY should not > uint128.max
-	acquireY = calc.MulDivFloor(transformLiquidityX, sqrtPrice_96, utils.Pow96)
+	acquireY = evm.MulDivFloor(transformLiquidityX, sqrtPrice_96, utils.Pow96)
 	newLiquidityX = new(big.Int).Add(liquidityX, transformLiquidityX)
 	return X2YAtPriceLiquidityResult{CostX: costX, AcquireY: acquireY, NewLiquidityX: newLiquidityX}
 }
@@ -87,12 +96,12 @@ type X2YRangeCompRet struct {
 	SqrtLoc_96 *big.Int
 }
 
-func x2YRangeComplete(rg RangeX2Y, desireY *big.Int) X2YRangeCompRet {
+func x2YRangeComplete(evm calc.EVM, rg RangeX2Y, desireY *big.Int) X2YRangeCompRet {
 	var ret X2YRangeCompRet
-	maxY := amountmath.GetAmountY(rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
+	maxY := amountmath.GetAmountYEVM(evm, rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
 	if maxY.Cmp(desireY) <= 0 {
 		ret.AcquireY = maxY
-		ret.CostX = amountmath.GetAmountX(rg.Liquidity, rg.LeftPt, rg.RightPt, rg.SqrtPriceR_96, rg.SqrtRate_96, true)
+		ret.CostX = amountmath.GetAmountXEVM(evm, rg.Liquidity, rg.LeftPt, rg.RightPt, rg.SqrtPriceR_96, rg.SqrtRate_96, true)
 		ret.CompleteLiquidity = true
 		return ret
 	}
@@ -127,9 +136,9 @@ func x2YRangeComplete(rg RangeX2Y, desireY *big.Int) X2YRangeCompRet {
 		// sqrtPricePrMloc_96 <= 1.0001 ** 25600 * 2 ^ 96 = 13 * 2^96 < 2^100
 		sqrtPricePrMloc_96, _ := calc.GetSqrtPrice(rg.RightPt - ret.LocPt)
 		// rg.sqrtPriceR_96 * TwoPower.Pow96 < 2^160 * 2^96 = 2^256
-		sqrtPricePrM1_96 := calc.MulDivCeil(rg.SqrtPriceR_96, utils.Pow96, rg.SqrtRate_96)
+		sqrtPricePrM1_96 := evm.MulDivCeil(rg.SqrtPriceR_96, utils.Pow96, rg.SqrtRate_96)
 		// rg.liquidity * (sqrtPricePrMloc_96 - TwoPower.Pow96) < 2^128 * 2^100 = 2^228 < 2^256
-		ret.CostX = calc.MulDivCeil(
+		ret.CostX = evm.MulDivCeil(
 			rg.Liquidity,
 			new(big.Int).Sub(sqrtPricePrMloc_96, utils.Pow96),
 			new(big.Int).Sub(rg.SqrtPriceR_96, sqrtPricePrM1_96),
@@ -152,14 +161,30 @@ func x2YRangeComplete(rg RangeX2Y, desireY *big.Int) X2YRangeCompRet {
 			),
 		)
 
-		acquireY256 := amountmath.GetAmountY(rg.Liquidity, sqrtLocA1_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
+		acquireY256 := amountmath.GetAmountYEVM(evm, rg.Liquidity, sqrtLocA1_96, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
 		// ret.acquireY <= desireY <= uint128.max
 		ret.AcquireY = calc.MinBigInt(acquireY256, desireY)
 	}
 	return ret
 }
 
-func X2YRange(
+// X2YRange swaps in range of [currentState.CurrentPoint, leftPt],
+// without checks of solidity integer types
+func X2YRange(currentState utils.State, leftPt int, sqrtRate_96 *big.Int, desireY *big.Int) X2YRangeRetState {
+	return X2YRangeEVM(calc.EVM{}, currentState, leftPt, sqrtRate_96, desireY)
+}
+
+// X2YRangeEVM is X2YRange checked by evm,
+// with a strict evm it reverts where the contract would overflow uint128
+func X2YRangeEVM(evm calc.EVM, currentState utils.State, leftPt int, sqrtRate_96 *big.Int, desireY *big.Int) X2YRangeRetState {
+	evm.RequireUint128(currentState.Liquidity, currentState.LiquidityX, desireY)
+	retState := x2YRange(evm, currentState, leftPt, sqrtRate_96, desireY)
+	evm.RequireUint128(retState.CostX, retState.AcquireY)
+	return retState
+}
+
+func x2YRange(
+	evm calc.EVM,
 	currentState utils.State,
 	leftPt int,
 	sqrtRate_96 *big.Int,
@@ -175,6 +200,7 @@ func X2YRange(
 	currentHasY := currentState.LiquidityX.Cmp(currentState.Liquidity) < 0
 	if currentHasY && (currentState.LiquidityX.Cmp(big.NewInt(0)) > 0 || leftPt == currentState.CurrentPoint) {
 		ret := x2YAtPriceLiquidity(
+			evm,
 			desireY, currentState.SqrtPrice_96, currentState.Liquidity, currentState.LiquidityX,
 		)
 		retState.CostX = ret.CostX
@@ -212,6 +238,7 @@ func X2YRange(
 	if leftPt < currentState.CurrentPoint {
 		sqrtPriceL_96, _ := calc.GetSqrtPrice(leftPt)
 		ret := x2YRangeComplete(
+			evm,
 			RangeX2Y{
 				Liquidity:     currentState.Liquidity,
 				SqrtPriceL_96: sqrtPriceL_96,
@@ -234,6 +261,7 @@ func X2YRange(
 			// locPt > leftPt
 			// trade at locPt
 			locRet := x2YAtPriceLiquidity(
+				evm,
 				desireY, ret.SqrtLoc_96, currentState.Liquidity, big.NewInt(0),
 			)
 			locCostX := locRet.CostX
@@ -24,15 +24,29 @@ type Y2XRangeRetState struct {
 	LiquidityX *big.Int
 }
 
+// Y2XAtPrice swaps for desireX (at most currX) at a single price,
+// without checks of solidity integer types
 func Y2XAtPrice(
 	desireX *big.Int,
 	sqrtPrice_96 *big.Int,
 	currX *big.Int,
 ) (costY, acquireX *big.Int) {
+	return Y2XAtPriceEVM(calc.EVM{}, desireX, sqrtPrice_96, currX)
+}
+
+// Y2XAtPriceEVM is Y2XAtPrice checked by evm
+func Y2XAtPriceEVM(
+	evm calc.EVM,
+	desireX *big.Int,
+	sqrtPrice_96 *big.Int,
+	currX *big.Int,
+) (costY, acquireX *big.Int) {
+	evm.RequireUint128(desireX, currX)
 	acquireX = calc.MinBigInt(desireX, currX)
-	l := calc.MulDivCeil(acquireX, sqrtPrice_96, utils.Pow96)
+	l := evm.MulDivCeil(acquireX, sqrtPrice_96, utils.Pow96)
 	// costY should <= uint128.max
-	costY = calc.MulDivCeil(l, sqrtPrice_96, utils.Pow96)
+	costY = evm.MulDivCeil(l, sqrtPrice_96, utils.Pow96)
+	evm.RequireUint128(costY, acquireX)
 	return costY, acquireX
 }
 
@@ -43,14 +57,15 @@ type Y2XAtPriceLiquidityResult struct {
 }
 
 func y2XAtPriceLiquidity(
+	evm calc.EVM,
 	desireX *big.Int,
 	sqrtPrice_96 *big.Int,
 	liquidityX *big.Int,
 ) Y2XAtPriceLiquidityResult {
-	maxTransformLiquidityY := calc.MulDivCeil(desireX, sqrtPrice_96, utils.Pow96)
+	maxTransformLiquidityY := evm.MulDivCeil(desireX, sqrtPrice_96, utils.Pow96)
 	// transformLiquidityY <= liquidityX <= uint128.max
 	transformLiquidityY := calc.MinBigInt(maxTransformLiquidityY, liquidityX)
-	costY := calc.MulDivCeil(transformLiquidityY, sqrtPrice_96, utils.Pow96)
+	costY := evm.MulDivCeil(transformLiquidityY, sqrtPrice_96, utils.Pow96)
 	// transformLiquidityY * TwoPower.Pow96 < 2^128 * 2^96 = 2^224 < 2^256
 	acquireX := new(big.Int).Mul(transformLiquidityY, utils.Pow96)
 	acquireX.Div(acquireX, sqrtPrice_96)
@@ -76,15 +91,16 @@ type Y2XRangeCompRet struct {
 }
 
 func y2XRangeComplete(
+	evm calc.EVM,
 	rg RangeY2X,
 	desireX *big.Int,
 ) Y2XRangeCompRet {
 	ret := Y2XRangeCompRet{}
-	maxX := amountmath.GetAmountX(rg.Liquidity, rg.LeftPt, rg.RightPt, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
+	maxX := amountmath.GetAmountXEVM(evm, rg.Liquidity, rg.LeftPt, rg.RightPt, rg.SqrtPriceR_96, rg.SqrtRate_96, false)
 	if maxX.Cmp(desireX) <= 0 {
 		// maxX <= desireX <= uint128.max
 		ret.AcquireX = maxX
-		ret.CostY = amountmath.GetAmountY(rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, true)
+		ret.CostY = amountmath.GetAmountYEVM(evm, rg.Liquidity, rg.SqrtPriceL_96, rg.SqrtPriceR_96, rg.SqrtRate_96, true)
 		ret.CompleteLiquidity = true
 		return ret
 	}
@@ -102,7 +118,7 @@ func y2XRangeComplete(
 	//  will enter the branch above and return
 	div := new(big.Int).Sub(
 		sqrtPricePrPl_96,
-		calc.MulDivFloor(
+		evm.MulDivFloor(
 			desireX,
 			new(big.Int).Sub(
 				rg.SqrtPriceR_96,
@@ -132,7 +148,8 @@ func y2XRangeComplete(
 
 	ret.CompleteLiquidity = false
 	// ret.acquireX <= desireX <= uint128.max
-	ret.AcquireX = calc.MinBigInt(amountmath.GetAmountX(
+	ret.AcquireX = calc.MinBigInt(amountmath.GetAmountXEVM(
+		evm,
 		rg.Liquidity,
 		rg.LeftPt,
 		ret.LocPt,
@@ -141,7 +158,8 @@ func y2XRangeComplete(
 		false,
 	), desireX)
 
-	ret.CostY = amountmath.GetAmountY(
+	ret.CostY = amountmath.GetAmountYEVM(
+		evm,
 		rg.Liquidity,
 		rg.SqrtPriceL_96,
 		ret.SqrtLoc_96,
@@ -151,7 +169,23 @@ func y2XRangeComplete(
 	return ret
 }
 
-func Y2XRange(
+// Y2XRange swaps in range of [currentState.CurrentPoint, rightPt],
+// without checks of solidity integer types
+func Y2XRange(currentState utils.State, rightPt int, sqrtRate_96 *big.Int, desireX *big.Int) Y2XRangeRetState {
+	return Y2XRangeEVM(calc.EVM{}, currentState, rightPt, sqrtRate_96, desireX)
+}
+
+// Y2XRangeEVM is Y2XRange checked by evm,
+// with a strict evm it reverts where the contract would overflow uint128
+func Y2XRangeEVM(evm calc.EVM, currentState utils.State, rightPt int, sqrtRate_96 *big.Int, desireX *big.Int) Y2XRangeRetState {
+	evm.RequireUint128(currentState.Liquidity, currentState.LiquidityX, desireX)
+	retState := y2XRange(evm, currentState, rightPt, sqrtRate_96, desireX)
+	evm.RequireUint128(retState.CostY, retState.AcquireX)
+	return retState
+}
+
+func y2XRange(
+	evm calc.EVM,
 	currentState utils.State,
 	rightPt int,
 	sqrtRate_96 *big.Int,
@@ -167,7 +201,7 @@ func Y2XRange(
 	// first, if current point is not all x, we can not move right directly
 	startHasY := currentState.LiquidityX.Cmp(currentState.Liquidity) < 0
 	if startHasY {
-		ret := y2XAtPriceLiquidity(desireX, currentState.SqrtPrice_96, currentState.LiquidityX)
+		ret := y2XAtPriceLiquidity(evm, desireX, currentState.SqrtPrice_96, currentState.LiquidityX)
 		retState.LiquidityX = ret.NewLiquidityX
 		retState.CostY = ret.CostY
 		retState.AcquireX = ret.AcquireX
@@ -197,6 +231,7 @@ func Y2XRange(
 	sqrtPriceR_96, _ := calc.GetSqrtPrice(rightPt)
 
 	ret := y2XRangeComplete(
+		evm,
 		RangeY2X{
 			Liquidity:     currentState.Liquidity,
 			SqrtPriceL_96: currentState.SqrtPrice_96,
@@ -217,7 +252,7 @@ func Y2XRange(
 		retState.FinalPt = rightPt
 		retState.SqrtFinalPrice_96 = sqrtPriceR_96
 	} else {
-		locRet := y2XAtPriceLiquidity(desireX, ret.SqrtLoc_96, currentState.Liquidity)
+		locRet := y2XAtPriceLiquidity(evm, desireX, ret.SqrtLoc_96, currentState.Liquidity)
 		locCostY := locRet.CostY
 		locAcquireX := locRet.AcquireX
 		retState.LiquidityX = locRet.NewLiquidityX
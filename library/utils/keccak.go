@@ -0,0 +1,12 @@
+package utils
+
+import "golang.org/x/crypto/sha3"
+
+// Keccak256 is the hash function used by EVM
+func Keccak256(data ...[]byte) []byte {
+	hash := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		hash.Write(d)
+	}
+	return hash.Sum(nil)
+}
@@ -0,0 +1,71 @@
+package limitorder
+
+import (
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// soldOf returns amount of selling token (ceil) an earn is paid for,
+// as UserEarn.updateUnlegacyOrder
+func soldOf(earn, sqrtPrice_96 *big.Int, sellXEarnY bool) *big.Int {
+	if sellXEarnY {
+		l := calc.MulDivCeil(earn, utils.Pow96, sqrtPrice_96)
+		return calc.MulDivCeil(l, utils.Pow96, sqrtPrice_96)
+	}
+	l := calc.MulDivCeil(earn, sqrtPrice_96, utils.Pow96)
+	return calc.MulDivCeil(l, sqrtPrice_96, utils.Pow96)
+}
+
+// earnOf returns amount of earned token (floor) a sold amount is worth
+func earnOf(sold, sqrtPrice_96 *big.Int, sellXEarnY bool) *big.Int {
+	if sellXEarnY {
+		l := calc.MulDivFloor(sold, sqrtPrice_96, utils.Pow96)
+		return calc.MulDivFloor(l, sqrtPrice_96, utils.Pow96)
+	}
+	l := calc.MulDivFloor(sold, utils.Pow96, sqrtPrice_96)
+	return calc.MulDivFloor(l, utils.Pow96, sqrtPrice_96)
+}
+
+// pointEarn returns pointers to earnings of the side earned by the order
+func pointEarn(limitOrder *swap.LimitOrderPoint, sellXEarnY bool) (earn, accEarn, legacyEarn, legacyAccEarn *big.Int) {
+	if sellXEarnY {
+		return limitOrder.EarnY, limitOrder.AccEarnY, limitOrder.LegacyEarnY, limitOrder.LegacyAccEarnY
+	}
+	return limitOrder.EarnX, limitOrder.AccEarnX, limitOrder.LegacyEarnX, limitOrder.LegacyAccEarnX
+}
+
+// update claims earnings of order from limit orders at its point like
+// UserEarn.updateLegacyOrder / updateUnlegacyOrder,
+// limitOrder and order are modified, it returns earn claimed
+func (order *Order) update(limitOrder *swap.LimitOrderPoint) *big.Int {
+	sqrtPrice_96, _ := calc.GetSqrtPrice(order.Point)
+	totalEarn, accEarn, legacyEarn, legacyAccEarn := pointEarn(limitOrder, order.SellXEarnY)
+
+	var earn *big.Int
+	if order.LastAccEarn.Cmp(legacyAccEarn) < 0 {
+		// all selling token at the point was sold after the last update,
+		// the order is filled and paid from legacy earn
+		earn = big.NewInt(0)
+		if order.SellingRemain.Sign() > 0 {
+			earn = calc.MinBigInt(earnOf(order.SellingRemain, sqrtPrice_96, order.SellXEarnY), legacyEarn)
+			order.SellingRemain = big.NewInt(0)
+		}
+		legacyEarn.Sub(legacyEarn, earn)
+		order.LegacyEarn.Add(order.LegacyEarn, earn)
+	} else {
+		earn = calc.MinBigInt(new(big.Int).Sub(accEarn, order.LastAccEarn), totalEarn)
+		sold := soldOf(earn, sqrtPrice_96, order.SellXEarnY)
+		if sold.Cmp(order.SellingRemain) > 0 {
+			sold = new(big.Int).Set(order.SellingRemain)
+			earn = earnOf(sold, sqrtPrice_96, order.SellXEarnY)
+		}
+		order.SellingRemain = new(big.Int).Sub(order.SellingRemain, sold)
+		totalEarn.Sub(totalEarn, earn)
+	}
+	order.LastAccEarn = new(big.Int).Set(accEarn)
+	order.Earn = new(big.Int).Add(order.Earn, earn)
+	return earn
+}
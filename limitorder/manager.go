@@ -0,0 +1,279 @@
+// Package limitorder emulates limit orders placed through the LimitOrderManager
+// contract on a pool, so fills and collectable amounts of each order can be
+// forecast offline after a sequence of swaps.
+//
+// every order claims its earnings from the limit orders of the pool at its point
+// like a user of the pool (UserEarn of the contract): it remembers accEarn of the
+// point at its last update, earnings since then are shared by orders at the point
+// in the order they are updated, and once all selling token at the point is sold
+// (legacy), the whole remain of the order is filled
+package limitorder
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Order is a limit order of the manager, amounts are in selling token
+// except Earn and LegacyEarn which are in earned token
+type Order struct {
+	ID    int
+	Owner string
+	Point int
+	// sell tokenX for tokenY if true, tokenY for tokenX otherwise
+	SellXEarnY bool
+	// selling token paid when the order is placed,
+	// including the part swapped immediately
+	Amount *big.Int
+	// selling token not yet sold
+	SellingRemain *big.Int
+	// selling token decreased and not yet collected
+	SellingDec *big.Int
+	// all selling token ever decreased
+	AccSellingDec *big.Int
+	// earned token not yet collected
+	Earn *big.Int
+	// part of all earned token paid from legacy earn of the point
+	LegacyEarn *big.Int
+	// accEarn of the point at last update
+	LastAccEarn *big.Int
+	// false after everything is collected
+	Active bool
+}
+
+// Filled returns selling token sold, Amount - SellingRemain - AccSellingDec
+func (order Order) Filled() *big.Int {
+	filled := new(big.Int).Sub(order.Amount, order.SellingRemain)
+	return filled.Sub(filled, order.AccSellingDec)
+}
+
+func (order *Order) clone() *Order {
+	clone := *order
+	for _, value := range []**big.Int{
+		&clone.Amount, &clone.SellingRemain, &clone.SellingDec, &clone.AccSellingDec,
+		&clone.Earn, &clone.LegacyEarn, &clone.LastAccEarn,
+	} {
+		*value = new(big.Int).Set(*value)
+	}
+	return &clone
+}
+
+// Manager holds a pool and orders placed on it
+type Manager struct {
+	pool   swap.PoolInfo
+	orders map[int]*Order
+	nextID int
+}
+
+// NewManager returns a manager without orders on pool,
+// limit orders already in pool are orders of other users
+func NewManager(pool swap.PoolInfo) *Manager {
+	return &Manager{pool: pool, orders: make(map[int]*Order)}
+}
+
+// Pool returns the current state of the pool
+func (manager *Manager) Pool() swap.PoolInfo {
+	return manager.pool
+}
+
+// Order returns a copy of the order as stored, earnings since its last update
+// are not included, see Preview
+func (manager *Manager) Order(id int) (Order, error) {
+	order, err := manager.order(id)
+	if err != nil {
+		return Order{}, err
+	}
+	return *order.clone(), nil
+}
+
+// Orders returns copies of orders of owner sorted by id, all orders if owner is empty
+func (manager *Manager) Orders(owner string) []Order {
+	var orders []Order
+	for _, order := range manager.orders {
+		if owner == "" || order.Owner == owner {
+			orders = append(orders, *order.clone())
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].ID < orders[j].ID
+	})
+	return orders
+}
+
+func (manager *Manager) order(id int) (*Order, error) {
+	order, ok := manager.orders[id]
+	if !ok {
+		return nil, fmt.Errorf("order %d not found", id)
+	}
+	return order, nil
+}
+
+// NewOrder simulates LimitOrderManager.newLimOrder, amount of selling token is
+// first swapped with the opposite limit orders at point, the rest rests as the order.
+// it returns the id of the order and token acquired by the immediate swap,
+// which is added to Earn of the order
+func (manager *Manager) NewOrder(owner string, point int, amount *big.Int, sellXEarnY bool) (int, *big.Int, error) {
+	var result swap.AddLimitOrderResult
+	var err error
+	if sellXEarnY {
+		result, err = swap.AddLimitOrderWithX(point, amount, manager.pool)
+	} else {
+		result, err = swap.AddLimitOrderWithY(point, amount, manager.pool)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	_, accEarn, _, _ := pointEarn(&result.LimitOrder, sellXEarnY)
+
+	manager.nextID++
+	manager.orders[manager.nextID] = &Order{
+		ID:            manager.nextID,
+		Owner:         owner,
+		Point:         point,
+		SellXEarnY:    sellXEarnY,
+		Amount:        new(big.Int).Set(result.Payed),
+		SellingRemain: new(big.Int).Set(result.Order),
+		SellingDec:    big.NewInt(0),
+		AccSellingDec: big.NewInt(0),
+		Earn:          new(big.Int).Set(result.Acquire),
+		LegacyEarn:    big.NewInt(0),
+		LastAccEarn:   new(big.Int).Set(accEarn),
+		Active:        true,
+	}
+	manager.pool = result.Pool
+	return manager.nextID, new(big.Int).Set(result.Acquire), nil
+}
+
+// Swap swaps on the pool and keeps the pool after the swap,
+// limit orders at traded points earn as the pool contract
+func (manager *Manager) Swap(swapType swap.SwapType, amount *big.Int, boundaryPt int) (swap.SwapResult, error) {
+	result, err := swap.Swap(swapType, amount, boundaryPt, manager.pool)
+	if err != nil {
+		return swap.SwapResult{}, err
+	}
+	manager.pool = swap.ApplySwap(manager.pool, result)
+	return result, nil
+}
+
+// update claims earnings of order and stores the limit orders at its point
+func (manager *Manager) update(order *Order) (*big.Int, error) {
+	limitOrder := manager.pool.LimitOrderAt(order.Point)
+	earn := order.update(&limitOrder)
+	pool, err := manager.pool.WithLimitOrder(limitOrder)
+	if err != nil {
+		return nil, err
+	}
+	manager.pool = pool
+	return earn, nil
+}
+
+// UpdateOrder simulates LimitOrderManager.updateOrder,
+// it returns earned token claimed from the point since the last update
+func (manager *Manager) UpdateOrder(id int) (*big.Int, error) {
+	order, err := manager.activeOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	return manager.update(order)
+}
+
+// Preview returns the order as if it is updated now, nothing is modified
+func (manager *Manager) Preview(id int) (Order, error) {
+	order, err := manager.order(id)
+	if err != nil {
+		return Order{}, err
+	}
+	preview := order.clone()
+	if preview.Active {
+		limitOrder := manager.pool.LimitOrderAt(preview.Point)
+		preview.update(&limitOrder)
+	}
+	return *preview, nil
+}
+
+// DecreaseOrder simulates LimitOrderManager.decLimOrder, the order is updated
+// and at most delta of its remain is cancelled into SellingDec,
+// nothing is cancelled if the order is filled. it returns the amount cancelled
+func (manager *Manager) DecreaseOrder(id int, delta *big.Int) (*big.Int, error) {
+	if delta.Sign() < 0 {
+		return nil, fmt.Errorf("negative delta %s", delta)
+	}
+	order, err := manager.activeOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := manager.update(order); err != nil {
+		return nil, err
+	}
+	actualDelta := new(big.Int).Set(delta)
+	if actualDelta.Cmp(order.SellingRemain) > 0 {
+		actualDelta.Set(order.SellingRemain)
+	}
+	if actualDelta.Sign() <= 0 {
+		return big.NewInt(0), nil
+	}
+
+	limitOrder := manager.pool.LimitOrderAt(order.Point)
+	selling := limitOrder.SellingY
+	if order.SellXEarnY {
+		selling = limitOrder.SellingX
+	}
+	if selling.Cmp(actualDelta) < 0 {
+		return nil, fmt.Errorf("order %d remains %s, more than %s sold at point %d", id, order.SellingRemain, selling, order.Point)
+	}
+	selling.Sub(selling, actualDelta)
+	pool, err := manager.pool.WithLimitOrder(limitOrder)
+	if err != nil {
+		return nil, err
+	}
+	manager.pool = pool
+
+	order.SellingRemain = new(big.Int).Sub(order.SellingRemain, actualDelta)
+	order.SellingDec = new(big.Int).Add(order.SellingDec, actualDelta)
+	order.AccSellingDec = new(big.Int).Add(order.AccSellingDec, actualDelta)
+	return actualDelta, nil
+}
+
+// CollectOrder simulates LimitOrderManager.collectLimOrder, the order is updated
+// and at most collectDec of SellingDec and collectEarn of Earn are collected,
+// the order becomes inactive when nothing is left. it returns amounts collected
+func (manager *Manager) CollectOrder(id int, collectDec, collectEarn *big.Int) (*big.Int, *big.Int, error) {
+	if collectDec.Sign() < 0 || collectEarn.Sign() < 0 {
+		return nil, nil, fmt.Errorf("negative amount to collect")
+	}
+	order, err := manager.activeOrder(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := manager.update(order); err != nil {
+		return nil, nil, err
+	}
+	actualDec := new(big.Int).Set(collectDec)
+	if actualDec.Cmp(order.SellingDec) > 0 {
+		actualDec.Set(order.SellingDec)
+	}
+	actualEarn := new(big.Int).Set(collectEarn)
+	if actualEarn.Cmp(order.Earn) > 0 {
+		actualEarn.Set(order.Earn)
+	}
+	order.SellingDec = new(big.Int).Sub(order.SellingDec, actualDec)
+	order.Earn = new(big.Int).Sub(order.Earn, actualEarn)
+	if order.SellingRemain.Sign() == 0 && order.SellingDec.Sign() == 0 && order.Earn.Sign() == 0 {
+		order.Active = false
+	}
+	return actualDec, actualEarn, nil
+}
+
+func (manager *Manager) activeOrder(id int) (*Order, error) {
+	order, err := manager.order(id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Active {
+		return nil, fmt.Errorf("order %d is not active", id)
+	}
+	return order, nil
+}
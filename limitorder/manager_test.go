@@ -0,0 +1,140 @@
+package limitorder
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swaptest"
+)
+
+func testPool(t *testing.T) swap.PoolInfo {
+	pool, err := swaptest.NewBuilder(2000).
+		SellY(-400, big.NewInt(30000)).
+		Build()
+	if err != nil {
+		t.Fatalf("build pool failed: %v", err)
+	}
+	return pool
+}
+
+func TestOrderFill(t *testing.T) {
+	manager := NewManager(testPool(t))
+	alice, acquire, err := manager.NewOrder("alice", 400, big.NewInt(100000), true)
+	if err != nil || acquire.Sign() != 0 {
+		t.Fatalf("new order failed: %v", err)
+	}
+	bob, _, err := manager.NewOrder("bob", 400, big.NewInt(50000), true)
+	if err != nil {
+		t.Fatalf("new order failed: %v", err)
+	}
+	if selling := manager.Pool().LimitOrderAt(400).SellingX; selling.Cmp(big.NewInt(150000)) != 0 {
+		t.Fatalf("selling tokenX at point %s, expect 150000", selling)
+	}
+
+	// partially fill the point
+	result, err := manager.Swap(swap.Y2X, big.NewInt(63000), 440)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if result.AmountX.Sign() <= 0 || result.AmountX.Cmp(big.NewInt(150000)) >= 0 {
+		t.Fatalf("swap should partially fill the point, acquired %s", result.AmountX)
+	}
+	earnY := manager.Pool().LimitOrderAt(400).EarnY
+
+	preview, err := manager.Preview(alice)
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	stored, _ := manager.Order(alice)
+	if stored.Earn.Sign() != 0 || preview.Earn.Sign() <= 0 || preview.Earn.Cmp(earnY) > 0 {
+		t.Fatalf("preview earn %s not expected, earnY of point %s", preview.Earn, earnY)
+	}
+	// filled amount is worth the earn at the price of the point,
+	// rounded up twice like UserEarn
+	diff := new(big.Int).Sub(preview.Filled(), result.AmountX)
+	if diff.Sign() < 0 || diff.Cmp(big.NewInt(2)) > 0 {
+		t.Fatalf("filled %s not match sold %s", preview.Filled(), result.AmountX)
+	}
+
+	earn, err := manager.UpdateOrder(alice)
+	if err != nil || earn.Cmp(preview.Earn) != 0 {
+		t.Fatalf("update earn %s not equal to preview %s: %v", earn, preview.Earn, err)
+	}
+	// alice is updated first and takes earnings of the point
+	if preview, _ = manager.Preview(bob); preview.Earn.Sign() != 0 {
+		t.Fatalf("bob should earn nothing yet, earn %s", preview.Earn)
+	}
+
+	// sell out the point, both orders are filled as legacy
+	if _, err = manager.Swap(swap.Y2X, big.NewInt(1000000), 440); err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	for _, id := range []int{alice, bob} {
+		if _, err := manager.UpdateOrder(id); err != nil {
+			t.Fatalf("update failed: %v", err)
+		}
+		order, _ := manager.Order(id)
+		if order.SellingRemain.Sign() != 0 || order.Filled().Cmp(order.Amount) != 0 || order.LegacyEarn.Sign() <= 0 {
+			t.Fatalf("order %d should be filled: %+v", id, order)
+		}
+	}
+	point := manager.Pool().LimitOrderAt(400)
+	total := big.NewInt(0)
+	for _, order := range manager.Orders("") {
+		total.Add(total, order.Earn)
+	}
+	claimed := new(big.Int).Sub(point.AccEarnY, point.LegacyEarnY)
+	if total.Cmp(claimed) != 0 || point.EarnY.Sign() != 0 {
+		t.Fatalf("orders earn %s, point accEarnY %s legacyEarnY %s", total, point.AccEarnY, point.LegacyEarnY)
+	}
+
+	order, _ := manager.Order(bob)
+	dec, collected, err := manager.CollectOrder(bob, big.NewInt(0), order.Earn)
+	if err != nil || dec.Sign() != 0 || collected.Cmp(order.Earn) != 0 {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if order, _ = manager.Order(bob); order.Active {
+		t.Fatalf("order should be inactive after collecting everything")
+	}
+	if _, err = manager.UpdateOrder(bob); err == nil {
+		t.Fatalf("update inactive order should fail")
+	}
+}
+
+func TestOrderDecrease(t *testing.T) {
+	manager := NewManager(testPool(t))
+	id, _, err := manager.NewOrder("carol", -400, big.NewInt(1000), false)
+	if err != nil {
+		t.Fatalf("new order failed: %v", err)
+	}
+	dec, err := manager.DecreaseOrder(id, big.NewInt(400))
+	if err != nil || dec.Cmp(big.NewInt(400)) != 0 {
+		t.Fatalf("decrease failed: %v", err)
+	}
+	if selling := manager.Pool().LimitOrderAt(-400).SellingY; selling.Cmp(big.NewInt(30600)) != 0 {
+		t.Fatalf("selling tokenY at point %s, expect 30600", selling)
+	}
+	dec, earn, err := manager.CollectOrder(id, big.NewInt(1000), big.NewInt(1000))
+	if err != nil || dec.Cmp(big.NewInt(400)) != 0 || earn.Sign() != 0 {
+		t.Fatalf("collect failed: %v", err)
+	}
+	order, _ := manager.Order(id)
+	if !order.Active || order.SellingRemain.Cmp(big.NewInt(600)) != 0 || order.Filled().Sign() != 0 {
+		t.Fatalf("order not expected: %+v", order)
+	}
+
+	// decrease more than remain
+	if dec, err = manager.DecreaseOrder(id, big.NewInt(1000)); err != nil || dec.Cmp(big.NewInt(600)) != 0 {
+		t.Fatalf("decrease failed: %v", err)
+	}
+	if _, _, err = manager.CollectOrder(id, big.NewInt(600), big.NewInt(0)); err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if order, _ = manager.Order(id); order.Active {
+		t.Fatalf("order should be inactive: %+v", order)
+	}
+	if _, err = manager.DecreaseOrder(42, big.NewInt(1)); err == nil {
+		t.Fatalf("unknown order should fail")
+	}
+}
@@ -0,0 +1,83 @@
+package poolkey
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+)
+
+// ParseAddress decodes a hex address with or without 0x prefix
+func ParseAddress(address string) ([]byte, error) {
+	address = strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	bytes, err := hex.DecodeString(address)
+	if err != nil || len(bytes) != 20 {
+		return nil, fmt.Errorf("invalid address %s", address)
+	}
+	return bytes, nil
+}
+
+// ChecksumAddress encodes a 20-byte address in EIP-55 mixed case
+func ChecksumAddress(address []byte) string {
+	lower := hex.EncodeToString(address)
+	hash := utils.Keccak256([]byte(lower))
+	result := []byte(lower)
+	for i, c := range result {
+		if c < 'a' {
+			continue
+		}
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0xf >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
+
+// create2Address computes address of contract deployed by deployer
+// with CREATE2, utils.Keccak256(0xff ++ deployer ++ salt ++ initCodeHash)[12:]
+func create2Address(deployer, salt, initCodeHash []byte) []byte {
+	return utils.Keccak256([]byte{0xff}, deployer, salt, initCodeHash)[12:]
+}
+
+// Salt returns utils.Keccak256(abi.encode(tokenX, tokenY, fee)),
+// the salt used by iZiSwapFactory.newPool
+func (key PoolKey) Salt() ([]byte, error) {
+	tokenX, err := ParseAddress(key.TokenX)
+	if err != nil {
+		return nil, err
+	}
+	tokenY, err := ParseAddress(key.TokenY)
+	if err != nil {
+		return nil, err
+	}
+	encoded := make([]byte, 96)
+	copy(encoded[12:32], tokenX)
+	copy(encoded[44:64], tokenY)
+	big.NewInt(int64(key.Fee)).FillBytes(encoded[64:96])
+	return utils.Keccak256(encoded), nil
+}
+
+// Address computes the pool address offline, the same as
+// factory.pool(tokenX, tokenY, fee), from address of the factory
+// and keccak256 of the pool creation code (initCodeHash)
+func (key PoolKey) Address(factory string, initCodeHash string) (string, error) {
+	factoryBytes, err := ParseAddress(factory)
+	if err != nil {
+		return "", err
+	}
+	initCodeHashBytes, err := hex.DecodeString(strings.TrimPrefix(initCodeHash, "0x"))
+	if err != nil || len(initCodeHashBytes) != 32 {
+		return "", fmt.Errorf("invalid init code hash %s", initCodeHash)
+	}
+	salt, err := key.Salt()
+	if err != nil {
+		return "", err
+	}
+	return ChecksumAddress(create2Address(factoryBytes, salt, initCodeHashBytes)), nil
+}
@@ -0,0 +1,98 @@
+package poolkey
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+)
+
+func TestCreate2Address(t *testing.T) {
+	// examples from EIP-1014
+	cases := []struct {
+		deployer string
+		salt     string
+		initCode string
+		address  string
+	}{
+		{
+			"0x0000000000000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"00",
+			"0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{
+			"0xdeadbeef00000000000000000000000000000000",
+			"0000000000000000000000000000000000000000000000000000000000000000",
+			"00",
+			"0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3",
+		},
+		{
+			"0xdeadbeef00000000000000000000000000000000",
+			"000000000000000000000000feed000000000000000000000000000000000000",
+			"00",
+			"0xD04116cDd17beBE565EB2422F2497E06cC1C9833",
+		},
+	}
+	for idx, c := range cases {
+		deployer, _ := ParseAddress(c.deployer)
+		salt, _ := hex.DecodeString(c.salt)
+		initCode, _ := hex.DecodeString(c.initCode)
+		address := ChecksumAddress(create2Address(deployer, salt, utils.Keccak256(initCode)))
+		if address != c.address {
+			t.Fatalf("case %d: address not equal (%s, %s)", idx, address, c.address)
+		}
+	}
+}
+
+// deployed pools whose factory uses the same CREATE2 salt as iZiSwapFactory,
+// keccak256(abi.encode(tokenX, tokenY, fee)): Uniswap V3 on ethereum mainnet
+const (
+	deployedFactory      = "0x1F98431c8aD98523631AE4a59f267346ea31F984"
+	deployedInitCodeHash = "0xe34f199b19b2b4f47f68442619d555527d244f78a3297ea89325f843f87b8b54"
+	usdc                 = "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"
+	weth                 = "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+)
+
+func TestPoolAddress(t *testing.T) {
+	for _, c := range []struct {
+		fee     int
+		address string
+	}{
+		{500, "0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640"},
+		{3000, "0x8ad599c3A0ff1De082011EFDDc58f1908eb6e6D8"},
+	} {
+		// token order does not matter
+		for _, tokens := range [][2]string{{usdc, weth}, {weth, usdc}} {
+			key, _ := New(tokens[0], tokens[1], c.fee)
+			address, err := key.Address(deployedFactory, deployedInitCodeHash)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if address != c.address {
+				t.Fatalf("address of fee %d not equal (%s, %s)", c.fee, address, c.address)
+			}
+		}
+	}
+	key, _ := New(usdc, weth, 500)
+	if _, err := key.Address("0x1234", deployedInitCodeHash); err == nil {
+		t.Fatalf("expect error for invalid factory")
+	}
+	if _, err := key.Address(deployedFactory, "0x1234"); err == nil {
+		t.Fatalf("expect error for invalid init code hash")
+	}
+}
+
+func TestPointDelta(t *testing.T) {
+	fees := []int{100, 400, 2000, 10000}
+	pointDeltas := []int{1, 8, 40, 200}
+	for idx, fee := range fees {
+		pointDelta, err := PointDelta(fee)
+		if err != nil || pointDelta != pointDeltas[idx] {
+			t.Fatalf("pointDelta of fee %d not equal (%d, %d)", fee, pointDelta, pointDeltas[idx])
+		}
+	}
+	if _, err := PointDelta(3000); err == nil {
+		t.Fatalf("expect error for fee not enabled")
+	}
+}
@@ -0,0 +1,26 @@
+package poolkey
+
+import "fmt"
+
+// fee tiers enabled in iZiSwapFactory, fee => pointDelta
+var feePointDelta = map[int]int{
+	100:   1,
+	400:   8,
+	2000:  40,
+	10000: 200,
+}
+
+// PointDelta returns pointDelta of pools with given fee,
+// which can be used to fill PoolInfo.PointDelta
+func PointDelta(fee int) (int, error) {
+	pointDelta, ok := feePointDelta[fee]
+	if !ok {
+		return 0, fmt.Errorf("fee %d not enabled", fee)
+	}
+	return pointDelta, nil
+}
+
+// PointDelta returns pointDelta of the pool
+func (key PoolKey) PointDelta() (int, error) {
+	return PointDelta(key.Fee)
+}
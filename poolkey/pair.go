@@ -0,0 +1,69 @@
+package poolkey
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Pair is a pool identified by its tokens, together with
+// the distribution of liquidity and limit orders of the pool
+type Pair struct {
+	PoolKey
+	Pool swap.PoolInfo
+}
+
+// PairQuote is the result of Pair.Quote with amounts labeled by token
+type PairQuote struct {
+	TokenIn   string
+	TokenOut  string
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	// the swap interface called
+	SwapType swap.SwapType
+	Result   swap.SwapResult
+}
+
+// Quote swaps tokenIn to tokenOut on the pair, amount is the input amount
+// if exactInput is true, otherwise the desired output amount,
+// limitPt is lowPt if tokenIn is tokenX or highPt if tokenIn is tokenY,
+// calc.MIN_POINT / calc.MAX_POINT can be used for no limit
+func (pair Pair) Quote(
+	tokenIn string,
+	tokenOut string,
+	amount *big.Int,
+	exactInput bool,
+	limitPt int,
+) (PairQuote, error) {
+	if pair.Pool.Fee != pair.Fee {
+		return PairQuote{}, fmt.Errorf("fee of pool info %d not match pair %s", pair.Pool.Fee, pair.PoolKey)
+	}
+	var swapType swap.SwapType
+	switch {
+	case pair.IsTokenX(tokenIn) && pair.IsTokenY(tokenOut):
+		swapType = swap.X2Y
+		if !exactInput {
+			swapType = swap.X2YDesireY
+		}
+	case pair.IsTokenY(tokenIn) && pair.IsTokenX(tokenOut):
+		swapType = swap.Y2X
+		if !exactInput {
+			swapType = swap.Y2XDesireX
+		}
+	default:
+		return PairQuote{}, fmt.Errorf("tokens %s, %s not match pair %s", tokenIn, tokenOut, pair.PoolKey)
+	}
+	swapResult, err := swap.Swap(swapType, amount, limitPt, pair.Pool)
+	if err != nil {
+		return PairQuote{}, err
+	}
+	return PairQuote{
+		TokenIn:   tokenIn,
+		TokenOut:  tokenOut,
+		AmountIn:  swapResult.AmountPayed(swapType),
+		AmountOut: swapResult.AmountAcquired(swapType),
+		SwapType:  swapType,
+		Result:    swapResult,
+	}, nil
+}
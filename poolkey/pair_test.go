@@ -0,0 +1,96 @@
+package poolkey
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+const (
+	tokenA = "0xd9145CCE52D386f254917e481eB44e9943F39138"
+	tokenB = "0x0B6cE8D0f6E4C3E8e12b5cDB4D5B5E4e5a4F1c2E"
+)
+
+func getPoolInfo() swap.PoolInfo {
+	return swap.PoolInfo{
+		CurrentPoint: 100,
+		PointDelta:   40,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          2000,
+		Liquidity:    big.NewInt(300000),
+		LiquidityX:   big.NewInt(100000),
+		Liquidities: []swap.LiquidityPoint{
+			{LiqudityDelta: big.NewInt(200000), Point: -5000},
+			{LiqudityDelta: big.NewInt(100000), Point: -3000},
+			{LiqudityDelta: big.NewInt(-100000), Point: 3000},
+			{LiqudityDelta: big.NewInt(-200000), Point: 5000},
+		},
+		LimitOrders: []swap.LimitOrderPoint{
+			{SellingY: big.NewInt(100000000000), Point: -1600},
+			{SellingX: big.NewInt(120000000000), Point: 1000},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	key, err := New(tokenA, tokenB, 2000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.TokenX != tokenB || key.TokenY != tokenA {
+		t.Fatalf("tokens not sorted (%s, %s)", key.TokenX, key.TokenY)
+	}
+	if _, err = New(tokenA, "0XD9145CCE52D386F254917E481EB44E9943F39138", 2000); err == nil {
+		t.Fatalf("expect error for identical tokens")
+	}
+	// 0x prefix does not change the order
+	key, err = New("d9145CCE52D386f254917e481eB44e9943F39138", tokenB, 2000)
+	if err != nil || key.TokenX != tokenB || !key.IsTokenY(tokenA) {
+		t.Fatalf("tokens without prefix not sorted: %+v %v", key, err)
+	}
+	if _, err = New(tokenA, "d9145cce52d386f254917e481eb44e9943f39138", 2000); err == nil {
+		t.Fatalf("expect error for identical tokens without prefix")
+	}
+	for _, token := range []string{"", "0x", "0x1234", "0xd9145CCE52D386f254917e481eB44e9943F3913g", tokenA + "00"} {
+		if _, err = New(token, tokenB, 2000); err == nil {
+			t.Fatalf("expect error for invalid token %q", token)
+		}
+	}
+}
+
+func TestPairQuote(t *testing.T) {
+	key, _ := New(tokenA, tokenB, 2000)
+	pair := Pair{PoolKey: key, Pool: getPoolInfo()}
+	amount := big.NewInt(150000000000)
+
+	// tokenA is tokenY, so this is y2x
+	quote, err := pair.Quote(tokenA, tokenB, amount, true, 5000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	swapResult, _ := swap.SwapY2X(amount, 5000, getPoolInfo())
+	if quote.SwapType != swap.Y2X {
+		t.Fatalf("swap type not equal (%s, %s)", quote.SwapType, swap.Y2X)
+	}
+	if quote.AmountIn.Cmp(swapResult.AmountY) != 0 || quote.AmountOut.Cmp(swapResult.AmountX) != 0 {
+		t.Fatalf("amounts not equal (%s, %s)", quote.AmountIn.String(), quote.AmountOut.String())
+	}
+
+	quote, err = pair.Quote(tokenB, tokenA, amount, false, -5000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	swapResult, _ = swap.SwapX2YDesireY(amount, -5000, getPoolInfo())
+	if quote.SwapType != swap.X2YDesireY {
+		t.Fatalf("swap type not equal (%s, %s)", quote.SwapType, swap.X2YDesireY)
+	}
+	if quote.AmountIn.Cmp(swapResult.AmountX) != 0 || quote.AmountOut.Cmp(swapResult.AmountY) != 0 {
+		t.Fatalf("amounts not equal (%s, %s)", quote.AmountIn.String(), quote.AmountOut.String())
+	}
+
+	if _, err = pair.Quote(tokenA, tokenA, amount, true, 5000); err == nil {
+		t.Fatalf("expect error for tokens not in pair")
+	}
+}
@@ -0,0 +1,60 @@
+package poolkey
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// PoolKey identifies an iZiSwap pool by its tokens and fee,
+// TokenX and TokenY are ordered like the factory does:
+// if address(tokenA) < address(tokenB) then tokenA is tokenX, tokenB is tokenY
+type PoolKey struct {
+	TokenX string
+	TokenY string
+	// fee of pool, 2000 means 0.2%
+	Fee int
+}
+
+// New sorts tokenA and tokenB into tokenX and tokenY by their 20-byte addresses,
+// with or without 0x prefix, invalid or identical addresses are rejected
+func New(tokenA, tokenB string, fee int) (PoolKey, error) {
+	addressA, err := ParseAddress(tokenA)
+	if err != nil {
+		return PoolKey{}, err
+	}
+	addressB, err := ParseAddress(tokenB)
+	if err != nil {
+		return PoolKey{}, err
+	}
+	switch bytes.Compare(addressA, addressB) {
+	case 0:
+		return PoolKey{}, fmt.Errorf("identical tokens %s", tokenA)
+	case -1:
+		return PoolKey{TokenX: tokenA, TokenY: tokenB, Fee: fee}, nil
+	}
+	return PoolKey{TokenX: tokenB, TokenY: tokenA, Fee: fee}, nil
+}
+
+// sameAddress reports whether a and b are the same valid address
+func sameAddress(a, b string) bool {
+	addressA, err := ParseAddress(a)
+	if err != nil {
+		return false
+	}
+	addressB, err := ParseAddress(b)
+	return err == nil && bytes.Equal(addressA, addressB)
+}
+
+// IsTokenX reports whether token is tokenX of the pool
+func (key PoolKey) IsTokenX(token string) bool {
+	return sameAddress(token, key.TokenX)
+}
+
+// IsTokenY reports whether token is tokenY of the pool
+func (key PoolKey) IsTokenY(token string) bool {
+	return sameAddress(token, key.TokenY)
+}
+
+func (key PoolKey) String() string {
+	return fmt.Sprintf("%s-%s-%d", key.TokenX, key.TokenY, key.Fee)
+}
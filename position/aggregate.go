@@ -0,0 +1,69 @@
+package position
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Range is liquidity of an owner in [LeftPt, RightPt), as positions are kept by an indexer
+type Range struct {
+	Owner     string
+	LeftPt    int
+	RightPt   int
+	Liquidity *big.Int
+}
+
+// covers reports whether liquidity of the range is active at point
+func (r Range) covers(point int) bool {
+	return r.LeftPt <= point && point < r.RightPt
+}
+
+// Aggregate converts ranges into liquidity deltas of the pool sorted by point,
+// with liquidity at currentPoint. deltas at the same point are merged, endpoints
+// whose deltas cancel out are kept with zero delta as the pool still has them
+func Aggregate(ranges []Range, currentPoint int) ([]swap.LiquidityPoint, *big.Int, error) {
+	deltas := make(map[int]*big.Int)
+	liquidity := big.NewInt(0)
+	for idx, r := range ranges {
+		if r.LeftPt >= r.RightPt {
+			return nil, nil, fmt.Errorf("range %d: leftPt %d not less than rightPt %d", idx, r.LeftPt, r.RightPt)
+		}
+		if r.Liquidity == nil || r.Liquidity.Sign() <= 0 {
+			return nil, nil, fmt.Errorf("range %d: liquidity %v is not positive", idx, r.Liquidity)
+		}
+		for _, end := range [2]int{r.LeftPt, r.RightPt} {
+			if deltas[end] == nil {
+				deltas[end] = big.NewInt(0)
+			}
+		}
+		deltas[r.LeftPt].Add(deltas[r.LeftPt], r.Liquidity)
+		deltas[r.RightPt].Sub(deltas[r.RightPt], r.Liquidity)
+		if r.covers(currentPoint) {
+			liquidity.Add(liquidity, r.Liquidity)
+		}
+	}
+
+	liquidities := make([]swap.LiquidityPoint, 0, len(deltas))
+	for point, delta := range deltas {
+		liquidities = append(liquidities, swap.LiquidityPoint{LiqudityDelta: delta, Point: point})
+	}
+	sort.Slice(liquidities, func(i, j int) bool {
+		return liquidities[i].Point < liquidities[j].Point
+	})
+	return liquidities, liquidity, nil
+}
+
+// ActiveAt returns indexes of ranges whose liquidity is active at point in ascending order,
+// their liquidity sums to the liquidity of the pool when the current point is point
+func ActiveAt(ranges []Range, point int) []int {
+	var active []int
+	for idx, r := range ranges {
+		if r.covers(point) {
+			active = append(active, idx)
+		}
+	}
+	return active
+}
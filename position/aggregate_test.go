@@ -0,0 +1,81 @@
+package position
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+func TestAggregate(t *testing.T) {
+	ranges := []Range{
+		{Owner: "alice", LeftPt: -400, RightPt: 400, Liquidity: big.NewInt(100)},
+		{Owner: "bob", LeftPt: 400, RightPt: 800, Liquidity: big.NewInt(100)},
+		{Owner: "carol", LeftPt: -800, RightPt: 400, Liquidity: big.NewInt(30)},
+		{Owner: "alice", LeftPt: 0, RightPt: 40, Liquidity: big.NewInt(5)},
+	}
+	liquidities, liquidity, err := Aggregate(ranges, 0)
+	if err != nil {
+		t.Fatalf("aggregate failed: %v", err)
+	}
+	expect := []struct {
+		point int
+		delta int64
+	}{
+		{-800, 30}, {-400, 100}, {0, 5}, {40, -5}, {400, -30}, {800, -100},
+	}
+	if len(liquidities) != len(expect) {
+		t.Fatalf("liquidities not expected: %+v", liquidities)
+	}
+	for idx, e := range expect {
+		if liquidities[idx].Point != e.point || liquidities[idx].LiqudityDelta.Cmp(big.NewInt(e.delta)) != 0 {
+			t.Fatalf("liquidity at %d is %+v, expect %+v", idx, liquidities[idx], e)
+		}
+	}
+	if liquidity.Cmp(big.NewInt(135)) != 0 {
+		t.Fatalf("liquidity at current point %s, expect 135", liquidity)
+	}
+
+	pool := swap.PoolInfo{
+		CurrentPoint: 0,
+		PointDelta:   40,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          2000,
+		Liquidity:    liquidity,
+		LiquidityX:   big.NewInt(0),
+		Liquidities:  liquidities,
+	}
+	if err := pool.Validate(); err != nil {
+		t.Fatalf("aggregated pool is invalid: %v", err)
+	}
+
+	for _, test := range []struct {
+		point  int
+		active []int
+	}{
+		{-801, nil},
+		{-800, []int{2}},
+		{0, []int{0, 2, 3}},
+		{40, []int{0, 2}},
+		{400, []int{1}},
+		{800, nil},
+	} {
+		active := ActiveAt(ranges, test.point)
+		if len(active) != len(test.active) {
+			t.Fatalf("active at %d: %v, expect %v", test.point, active, test.active)
+		}
+		for idx := range active {
+			if active[idx] != test.active[idx] {
+				t.Fatalf("active at %d: %v, expect %v", test.point, active, test.active)
+			}
+		}
+	}
+
+	if _, _, err := Aggregate([]Range{{LeftPt: 40, RightPt: 40, Liquidity: big.NewInt(1)}}, 0); err == nil {
+		t.Fatalf("empty range should fail")
+	}
+	if _, _, err := Aggregate([]Range{{LeftPt: 0, RightPt: 40, Liquidity: big.NewInt(0)}}, 0); err == nil {
+		t.Fatalf("zero liquidity should fail")
+	}
+}
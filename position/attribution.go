@@ -0,0 +1,65 @@
+package position
+
+import (
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Share is the part of a swap taken by liquidity of a range
+type Share struct {
+	// input token (fee excluded) paid to and output token acquired from
+	// liquidity of the range, each range step is split by liquidity rounded down
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	// fee scale inside the range added by the swap
+	FeeScale_128 *big.Int
+	// fee earned in input token, FeeScale_128 * liquidity / 2^128 rounded down,
+	// as the position collects it if updated right before and after the swap
+	Fee *big.Int
+}
+
+// Attribute splits a swap recorded by swap.TraceSwap among ranges, liquidity of
+// a step is shared by ranges active at its point in proportion to their liquidity.
+// fee of limit orders traded with liquidity at the point goes to liquidity as well.
+// it returns shares in the order of ranges, ranges not in the swap have zero shares
+func Attribute(swapType swap.SwapType, trace swap.Trace, ranges []Range) []Share {
+	shares := make([]Share, len(ranges))
+	for idx := range shares {
+		shares[idx] = Share{
+			AmountIn:     big.NewInt(0),
+			AmountOut:    big.NewInt(0),
+			FeeScale_128: big.NewInt(0),
+			Fee:          big.NewInt(0),
+		}
+	}
+
+	for _, step := range trace {
+		if step.Kind == swap.StepEndpoint || step.Liquidity == nil || step.Liquidity.Sign() == 0 {
+			continue
+		}
+		amountIn, amountOut := step.AmountY, step.AmountX
+		if swapType.IsX2Y() {
+			amountIn, amountOut = step.AmountX, step.AmountY
+		}
+		amountIn = new(big.Int).Sub(amountIn, step.FeeAmount)
+		for _, idx := range ActiveAt(ranges, step.FromPoint) {
+			share := &shares[idx]
+			if step.FeeScaleDelta_128 != nil {
+				share.FeeScale_128.Add(share.FeeScale_128, step.FeeScaleDelta_128)
+			}
+			if step.Kind != swap.StepRange {
+				continue
+			}
+			liquidity := ranges[idx].Liquidity
+			share.AmountIn.Add(share.AmountIn, calc.MulDivFloor(amountIn, liquidity, step.Liquidity))
+			share.AmountOut.Add(share.AmountOut, calc.MulDivFloor(amountOut, liquidity, step.Liquidity))
+		}
+	}
+
+	for idx := range shares {
+		shares[idx].Fee = calc.MulDivFloor(shares[idx].FeeScale_128, ranges[idx].Liquidity, pow128)
+	}
+	return shares
+}
@@ -0,0 +1,70 @@
+package position
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+func TestAttribute(t *testing.T) {
+	ranges := []Range{
+		{Owner: "alice", LeftPt: -400, RightPt: 400, Liquidity: big.NewInt(1000000)},
+		{Owner: "bob", LeftPt: -2000, RightPt: 400, Liquidity: big.NewInt(3000000)},
+		{Owner: "carol", LeftPt: 400, RightPt: 800, Liquidity: big.NewInt(1000000)},
+	}
+	liquidities, liquidity, err := Aggregate(ranges, 0)
+	if err != nil {
+		t.Fatalf("aggregate failed: %v", err)
+	}
+	pool := testPool(t)
+	pool.FeeChargePercent = 20
+	pool.Liquidities = liquidities
+	pool.Liquidity = liquidity
+	pool.LimitOrders = []swap.LimitOrderPoint{{Point: -200, SellingY: big.NewInt(1000000)}}
+
+	result, trace, err := swap.TraceSwap(swap.X2Y, big.NewInt(2000000000), -1000, pool)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if result.CurrentPoint >= -400 || result.Events.LimitOrders != 1 {
+		t.Fatalf("swap should trade the limit order and cross -400, stopped at %d", result.CurrentPoint)
+	}
+	shares := Attribute(swap.X2Y, trace, ranges)
+	after := swap.ApplySwap(pool, result)
+
+	// fee matches fee scales inside each range
+	for idx, r := range ranges {
+		beforeX, _ := pool.FeeScaleInside(r.LeftPt, r.RightPt)
+		afterX, _ := after.FeeScaleInside(r.LeftPt, r.RightPt)
+		expect := fee(afterX, beforeX, r.Liquidity)
+		if shares[idx].Fee.Cmp(expect) != 0 {
+			t.Fatalf("fee of %s %s, expect %s from fee scales", r.Owner, shares[idx].Fee, expect)
+		}
+	}
+	if shares[2].Fee.Sign() != 0 || shares[2].AmountIn.Sign() != 0 || shares[2].AmountOut.Sign() != 0 {
+		t.Fatalf("range out of the swap should take nothing: %+v", shares[2])
+	}
+	if shares[0].Fee.Sign() <= 0 || shares[1].Fee.Cmp(shares[0].Fee) <= 0 {
+		t.Fatalf("fees not expected: %s %s", shares[0].Fee, shares[1].Fee)
+	}
+
+	// volume of ranges adds up to volume of range steps, up to rounding
+	amountIn, amountOut := big.NewInt(0), big.NewInt(0)
+	steps := 0
+	for _, step := range trace {
+		if step.Kind == swap.StepRange {
+			amountIn.Add(amountIn, new(big.Int).Sub(step.AmountX, step.FeeAmount))
+			amountOut.Add(amountOut, step.AmountY)
+			steps++
+		}
+	}
+	for _, share := range shares {
+		amountIn.Sub(amountIn, share.AmountIn)
+		amountOut.Sub(amountOut, share.AmountOut)
+	}
+	maxLost := big.NewInt(int64(len(ranges) * steps))
+	if amountIn.Sign() < 0 || amountIn.Cmp(maxLost) > 0 || amountOut.Sign() < 0 || amountOut.Cmp(maxLost) > 0 {
+		t.Fatalf("volume not attributed: %s %s left", amountIn, amountOut)
+	}
+}
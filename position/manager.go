@@ -0,0 +1,297 @@
+// Package position emulates liquidity positions (NFTs) of the LiquidityManager
+// contract on a pool, so fees earned and tokens collectable by each position
+// can be forecast offline after a sequence of swaps.
+//
+// like the contract, a position remembers fee scales inside its range at its last
+// update, fees since then are (fee scale inside - last fee scale) * liquidity / 2^128
+// and are added to remaining tokens with tokens of decreased liquidity
+package position
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+var (
+	pow128 = new(big.Int).Lsh(big.NewInt(1), 128)
+	pow256 = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// Position is a liquidity position of the manager
+type Position struct {
+	ID      int
+	Owner   string
+	LeftPt  int
+	RightPt int
+	// liquidity of the position in [LeftPt, RightPt)
+	Liquidity *big.Int
+	// fee scales inside the range at last update
+	LastFeeScaleX_128 *big.Int
+	LastFeeScaleY_128 *big.Int
+	// fees and tokens of decreased liquidity not yet collected
+	RemainTokenX *big.Int
+	RemainTokenY *big.Int
+	// false after all liquidity is decreased and everything is collected
+	Active bool
+}
+
+func (position *Position) clone() *Position {
+	clone := *position
+	for _, value := range []**big.Int{
+		&clone.Liquidity, &clone.LastFeeScaleX_128, &clone.LastFeeScaleY_128,
+		&clone.RemainTokenX, &clone.RemainTokenY,
+	} {
+		*value = new(big.Int).Set(*value)
+	}
+	return &clone
+}
+
+// fee returns fee earned by liquidity since lastFeeScale_128
+func fee(feeScale_128, lastFeeScale_128, liquidity *big.Int) *big.Int {
+	delta := new(big.Int).Sub(feeScale_128, lastFeeScale_128)
+	delta.Mod(delta, pow256)
+	return calc.MulDivFloor(delta, liquidity, pow128)
+}
+
+// update adds fees earned since the last update to remaining tokens
+// and remembers fee scales inside the range
+func (position *Position) update(accFeeXIn_128, accFeeYIn_128 *big.Int) {
+	feeX := fee(accFeeXIn_128, position.LastFeeScaleX_128, position.Liquidity)
+	feeY := fee(accFeeYIn_128, position.LastFeeScaleY_128, position.Liquidity)
+	position.RemainTokenX = new(big.Int).Add(position.RemainTokenX, feeX)
+	position.RemainTokenY = new(big.Int).Add(position.RemainTokenY, feeY)
+	position.LastFeeScaleX_128 = new(big.Int).Set(accFeeXIn_128)
+	position.LastFeeScaleY_128 = new(big.Int).Set(accFeeYIn_128)
+}
+
+// Deposit is liquidity added to a position and tokens paid for it
+type Deposit struct {
+	Liquidity *big.Int
+	AmountX   *big.Int
+	AmountY   *big.Int
+}
+
+// Manager holds a pool and positions minted on it
+type Manager struct {
+	pool      swap.PoolInfo
+	positions map[int]*Position
+	nextID    int
+}
+
+// NewManager returns a manager without positions on pool,
+// liquidity already in pool belongs to other users
+func NewManager(pool swap.PoolInfo) *Manager {
+	return &Manager{pool: pool, positions: make(map[int]*Position)}
+}
+
+// Pool returns the current state of the pool
+func (manager *Manager) Pool() swap.PoolInfo {
+	return manager.pool
+}
+
+// Position returns a copy of the position as stored, fees since its last update
+// are not included, see Preview
+func (manager *Manager) Position(id int) (Position, error) {
+	position, err := manager.position(id)
+	if err != nil {
+		return Position{}, err
+	}
+	return *position.clone(), nil
+}
+
+// Positions returns copies of positions of owner sorted by id, all positions if owner is empty
+func (manager *Manager) Positions(owner string) []Position {
+	var positions []Position
+	for _, position := range manager.positions {
+		if owner == "" || position.Owner == owner {
+			positions = append(positions, *position.clone())
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].ID < positions[j].ID
+	})
+	return positions
+}
+
+func (manager *Manager) position(id int) (*Position, error) {
+	position, ok := manager.positions[id]
+	if !ok {
+		return nil, fmt.Errorf("position %d not found", id)
+	}
+	return position, nil
+}
+
+func (manager *Manager) activePosition(id int) (*Position, error) {
+	position, err := manager.position(id)
+	if err != nil {
+		return nil, err
+	}
+	if !position.Active {
+		return nil, fmt.Errorf("position %d is not active", id)
+	}
+	return position, nil
+}
+
+// computeLiquidity returns the max liquidity of [leftPt, rightPt) paid by at most
+// xLim tokenX and yLim tokenY, as LiquidityManager._computeLiquidity
+func (manager *Manager) computeLiquidity(leftPt, rightPt int, xLim, yLim *big.Int) (*big.Int, error) {
+	// tokens deposited per 2^96 liquidity
+	unit, err := swap.Mint(leftPt, rightPt, utils.Pow96, manager.pool)
+	if err != nil {
+		return nil, err
+	}
+	liquidity := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	if unit.AmountX.Sign() > 0 {
+		xl := new(big.Int).Mul(xLim, utils.Pow96)
+		liquidity = calc.MinBigInt(liquidity, xl.Div(xl, unit.AmountX))
+	}
+	if unit.AmountY.Sign() > 0 {
+		// yLim - 1 like the contract, as tokenY in the range and
+		// at the current point are rounded up separately
+		yl := calc.MaxBigInt(new(big.Int).Sub(yLim, big.NewInt(1)), big.NewInt(0))
+		yl.Mul(yl, utils.Pow96)
+		liquidity = calc.MinBigInt(liquidity, yl.Div(yl, unit.AmountY))
+	}
+	return liquidity, nil
+}
+
+// deposit mints liquidity paid by at most xLim tokenX and yLim tokenY
+func (manager *Manager) deposit(leftPt, rightPt int, xLim, yLim *big.Int) (Deposit, swap.LiquidityResult, error) {
+	if xLim.Sign() < 0 || yLim.Sign() < 0 {
+		return Deposit{}, swap.LiquidityResult{}, fmt.Errorf("negative token limit")
+	}
+	liquidity, err := manager.computeLiquidity(leftPt, rightPt, xLim, yLim)
+	if err != nil {
+		return Deposit{}, swap.LiquidityResult{}, err
+	}
+	result, err := swap.Mint(leftPt, rightPt, liquidity, manager.pool)
+	if err != nil {
+		return Deposit{}, swap.LiquidityResult{}, err
+	}
+	if result.AmountX.Cmp(xLim) > 0 || result.AmountY.Cmp(yLim) > 0 {
+		return Deposit{}, swap.LiquidityResult{}, fmt.Errorf(
+			"deposit of %s tokenX and %s tokenY exceeds limits %s and %s",
+			result.AmountX, result.AmountY, xLim, yLim,
+		)
+	}
+	manager.pool = result.Pool
+	return Deposit{Liquidity: liquidity, AmountX: result.AmountX, AmountY: result.AmountY}, result, nil
+}
+
+// Mint simulates LiquidityManager.mint, the max liquidity of [leftPt, rightPt) paid by
+// at most xLim tokenX and yLim tokenY is minted as a new position.
+// it returns the id of the position and the deposit
+func (manager *Manager) Mint(owner string, leftPt, rightPt int, xLim, yLim *big.Int) (int, Deposit, error) {
+	deposit, result, err := manager.deposit(leftPt, rightPt, xLim, yLim)
+	if err != nil {
+		return 0, Deposit{}, err
+	}
+	manager.nextID++
+	manager.positions[manager.nextID] = &Position{
+		ID:                manager.nextID,
+		Owner:             owner,
+		LeftPt:            leftPt,
+		RightPt:           rightPt,
+		Liquidity:         new(big.Int).Set(deposit.Liquidity),
+		LastFeeScaleX_128: result.AccFeeXIn_128,
+		LastFeeScaleY_128: result.AccFeeYIn_128,
+		RemainTokenX:      big.NewInt(0),
+		RemainTokenY:      big.NewInt(0),
+		Active:            true,
+	}
+	return manager.nextID, deposit, nil
+}
+
+// AddLiquidity simulates LiquidityManager.addLiquidity, fees are updated
+// and liquidity paid by at most xLim tokenX and yLim tokenY is added to the position
+func (manager *Manager) AddLiquidity(id int, xLim, yLim *big.Int) (Deposit, error) {
+	position, err := manager.activePosition(id)
+	if err != nil {
+		return Deposit{}, err
+	}
+	deposit, result, err := manager.deposit(position.LeftPt, position.RightPt, xLim, yLim)
+	if err != nil {
+		return Deposit{}, err
+	}
+	position.update(result.AccFeeXIn_128, result.AccFeeYIn_128)
+	position.Liquidity = new(big.Int).Add(position.Liquidity, deposit.Liquidity)
+	return deposit, nil
+}
+
+// DecLiquidity simulates LiquidityManager.decLiquidity, fees are updated and at most
+// liquidDelta of the position is burned, tokens withdrawn are added to remaining tokens.
+// it returns tokenX and tokenY withdrawn
+func (manager *Manager) DecLiquidity(id int, liquidDelta *big.Int) (*big.Int, *big.Int, error) {
+	if liquidDelta.Sign() < 0 {
+		return nil, nil, fmt.Errorf("negative liquidity %s", liquidDelta)
+	}
+	position, err := manager.activePosition(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	actualDelta := calc.MinBigInt(liquidDelta, position.Liquidity)
+	result, err := swap.Burn(position.LeftPt, position.RightPt, actualDelta, manager.pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager.pool = result.Pool
+	position.update(result.AccFeeXIn_128, result.AccFeeYIn_128)
+	position.Liquidity = new(big.Int).Sub(position.Liquidity, actualDelta)
+	position.RemainTokenX = new(big.Int).Add(position.RemainTokenX, result.AmountX)
+	position.RemainTokenY = new(big.Int).Add(position.RemainTokenY, result.AmountY)
+	return result.AmountX, result.AmountY, nil
+}
+
+// Collect simulates LiquidityManager.collect, fees are updated and at most amountXLim
+// tokenX and amountYLim tokenY of remaining tokens are collected, the position becomes
+// inactive when it has neither liquidity nor remaining tokens. it returns amounts collected
+func (manager *Manager) Collect(id int, amountXLim, amountYLim *big.Int) (*big.Int, *big.Int, error) {
+	if amountXLim.Sign() < 0 || amountYLim.Sign() < 0 {
+		return nil, nil, fmt.Errorf("negative amount to collect")
+	}
+	position, err := manager.activePosition(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if position.Liquidity.Sign() > 0 {
+		accFeeXIn_128, accFeeYIn_128 := manager.pool.FeeScaleInside(position.LeftPt, position.RightPt)
+		position.update(accFeeXIn_128, accFeeYIn_128)
+	}
+	amountX := calc.MinBigInt(amountXLim, position.RemainTokenX)
+	amountY := calc.MinBigInt(amountYLim, position.RemainTokenY)
+	position.RemainTokenX = new(big.Int).Sub(position.RemainTokenX, amountX)
+	position.RemainTokenY = new(big.Int).Sub(position.RemainTokenY, amountY)
+	if position.Liquidity.Sign() == 0 && position.RemainTokenX.Sign() == 0 && position.RemainTokenY.Sign() == 0 {
+		position.Active = false
+	}
+	return amountX, amountY, nil
+}
+
+// Preview returns the position as if it is updated now, nothing is modified
+func (manager *Manager) Preview(id int) (Position, error) {
+	position, err := manager.position(id)
+	if err != nil {
+		return Position{}, err
+	}
+	preview := position.clone()
+	if preview.Active && preview.Liquidity.Sign() > 0 {
+		preview.update(manager.pool.FeeScaleInside(preview.LeftPt, preview.RightPt))
+	}
+	return *preview, nil
+}
+
+// Swap swaps on the pool and keeps the pool after the swap,
+// fees are distributed to liquidity as the pool contract
+func (manager *Manager) Swap(swapType swap.SwapType, amount *big.Int, boundaryPt int) (swap.SwapResult, error) {
+	result, err := swap.Swap(swapType, amount, boundaryPt, manager.pool)
+	if err != nil {
+		return swap.SwapResult{}, err
+	}
+	manager.pool = swap.ApplySwap(manager.pool, result)
+	return result, nil
+}
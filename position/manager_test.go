@@ -0,0 +1,185 @@
+package position
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swaptest"
+)
+
+func testPool(t *testing.T) swap.PoolInfo {
+	pool, err := swaptest.NewBuilder(2000).Build()
+	if err != nil {
+		t.Fatalf("build pool failed: %v", err)
+	}
+	return pool
+}
+
+func TestPositionFee(t *testing.T) {
+	manager := NewManager(testPool(t))
+	limit := big.NewInt(1000000000)
+	alice, deposit, err := manager.Mint("alice", -400, 400, limit, limit)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	if deposit.Liquidity.Sign() <= 0 || deposit.AmountX.Cmp(limit) > 0 || deposit.AmountY.Cmp(limit) > 0 {
+		t.Fatalf("deposit not expected: %+v", deposit)
+	}
+	bob, _, err := manager.Mint("bob", -400, 400, limit, limit)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	// out of range of the swaps
+	carol, _, err := manager.Mint("carol", 400, 800, limit, big.NewInt(0))
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	// liquidity of bob is doubled
+	if _, err := manager.AddLiquidity(bob, limit, limit); err != nil {
+		t.Fatalf("add liquidity failed: %v", err)
+	}
+
+	result, trace, err := swap.TraceSwap(swap.X2Y, big.NewInt(100000000), -400, manager.Pool())
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if _, err := manager.Swap(swap.X2Y, big.NewInt(100000000), -400); err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	feeX := big.NewInt(0)
+	for _, step := range trace {
+		if step.FeeAmount != nil {
+			feeX.Add(feeX, step.FeeAmount)
+		}
+	}
+
+	alicePreview, _ := manager.Preview(alice)
+	bobPreview, _ := manager.Preview(bob)
+	carolPreview, _ := manager.Preview(carol)
+	if carolPreview.RemainTokenX.Sign() != 0 || carolPreview.RemainTokenY.Sign() != 0 {
+		t.Fatalf("position out of range should earn nothing: %+v", carolPreview)
+	}
+	// bob has twice the liquidity of alice
+	earned := new(big.Int).Add(alicePreview.RemainTokenX, bobPreview.RemainTokenX)
+	lost := new(big.Int).Sub(feeX, earned)
+	if lost.Sign() < 0 || lost.Cmp(big.NewInt(int64(2*len(trace)))) > 0 {
+		t.Fatalf("fee %s of swap %s not shared by positions: alice %s bob %s", feeX, result.AmountX, alicePreview.RemainTokenX, bobPreview.RemainTokenX)
+	}
+	diff := new(big.Int).Sub(bobPreview.RemainTokenX, new(big.Int).Mul(alicePreview.RemainTokenX, big.NewInt(2)))
+	if diff.CmpAbs(big.NewInt(2)) > 0 {
+		t.Fatalf("bob should earn twice of alice: %s %s", bobPreview.RemainTokenX, alicePreview.RemainTokenX)
+	}
+	if stored, _ := manager.Position(alice); stored.RemainTokenX.Sign() != 0 {
+		t.Fatalf("preview should not update the position")
+	}
+
+	// decrease all liquidity and collect everything
+	amountX, amountY, err := manager.DecLiquidity(alice, new(big.Int).Lsh(big.NewInt(1), 100))
+	if err != nil {
+		t.Fatalf("dec liquidity failed: %v", err)
+	}
+	position, _ := manager.Position(alice)
+	if position.Liquidity.Sign() != 0 ||
+		position.RemainTokenX.Cmp(new(big.Int).Add(amountX, alicePreview.RemainTokenX)) != 0 ||
+		position.RemainTokenY.Cmp(amountY) != 0 {
+		t.Fatalf("position after dec liquidity not expected: %+v", position)
+	}
+	collectX, collectY, err := manager.Collect(alice, new(big.Int).Lsh(limit, 10), new(big.Int).Lsh(limit, 10))
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if collectX.Cmp(position.RemainTokenX) != 0 || collectY.Cmp(position.RemainTokenY) != 0 {
+		t.Fatalf("collected %s %s, expect %s %s", collectX, collectY, position.RemainTokenX, position.RemainTokenY)
+	}
+	if position, _ = manager.Position(alice); position.Active {
+		t.Fatalf("position should be inactive after everything is collected")
+	}
+	if _, _, err := manager.Collect(alice, limit, limit); err == nil {
+		t.Fatalf("collect of inactive position should fail")
+	}
+
+	// fees of bob are collected without decreasing liquidity
+	collectX, _, err = manager.Collect(bob, big.NewInt(10), limit)
+	if err != nil || collectX.Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("collect failed: %s %v", collectX, err)
+	}
+	position, _ = manager.Position(bob)
+	if position.RemainTokenX.Cmp(new(big.Int).Sub(bobPreview.RemainTokenX, big.NewInt(10))) != 0 || !position.Active {
+		t.Fatalf("position after collect not expected: %+v", position)
+	}
+}
+
+func TestPositionLiquidity(t *testing.T) {
+	manager := NewManager(testPool(t))
+	limit := big.NewInt(1000000000)
+	alice, deposit, err := manager.Mint("alice", -400, 400, limit, limit)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	if deposit.Liquidity.Sign() <= 0 || deposit.AmountX.Cmp(limit) > 0 || deposit.AmountY.Cmp(limit) > 0 {
+		t.Fatalf("deposit not expected: %+v", deposit)
+	}
+	if _, _, err := manager.Mint("bob", 400, 800, limit, big.NewInt(0)); err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	if _, _, err := manager.Mint("carol", -400, 400, big.NewInt(-1), limit); err == nil {
+		t.Fatalf("negative limit should fail")
+	}
+	// liquidity of alice is doubled
+	added, err := manager.AddLiquidity(alice, limit, limit)
+	if err != nil {
+		t.Fatalf("add liquidity failed: %v", err)
+	}
+	if added.Liquidity.Cmp(deposit.Liquidity) != 0 {
+		t.Fatalf("added liquidity %s, expect %s", added.Liquidity, deposit.Liquidity)
+	}
+	if positions := manager.Positions("alice"); len(positions) != 1 || positions[0].ID != alice {
+		t.Fatalf("positions of alice not expected: %+v", positions)
+	}
+	if positions := manager.Positions(""); len(positions) != 2 {
+		t.Fatalf("expect 2 positions, got %d", len(positions))
+	}
+
+	// decrease all liquidity, tokens withdrawn are rounded down
+	amountX, amountY, err := manager.DecLiquidity(alice, new(big.Int).Lsh(big.NewInt(1), 100))
+	if err != nil {
+		t.Fatalf("dec liquidity failed: %v", err)
+	}
+	for _, diff := range []*big.Int{
+		new(big.Int).Sub(new(big.Int).Add(deposit.AmountX, added.AmountX), amountX),
+		new(big.Int).Sub(new(big.Int).Add(deposit.AmountY, added.AmountY), amountY),
+	} {
+		if diff.Sign() < 0 || diff.Cmp(big.NewInt(4)) > 0 {
+			t.Fatalf("withdrawn %s %s not expected", amountX, amountY)
+		}
+	}
+	position, _ := manager.Position(alice)
+	if position.Liquidity.Sign() != 0 || position.RemainTokenX.Cmp(amountX) != 0 || position.RemainTokenY.Cmp(amountY) != 0 {
+		t.Fatalf("position after dec liquidity not expected: %+v", position)
+	}
+	if manager.Pool().Liquidity.Sign() != 0 {
+		t.Fatalf("liquidity of pool should be burned, got %s", manager.Pool().Liquidity)
+	}
+
+	collectX, _, err := manager.Collect(alice, big.NewInt(10), big.NewInt(0))
+	if err != nil || collectX.Cmp(big.NewInt(10)) != 0 {
+		t.Fatalf("collect failed: %s %v", collectX, err)
+	}
+	if position, _ = manager.Position(alice); !position.Active {
+		t.Fatalf("position with remaining tokens should be active")
+	}
+	collectX, collectY, err := manager.Collect(alice, new(big.Int).Lsh(limit, 10), new(big.Int).Lsh(limit, 10))
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if collectX.Cmp(new(big.Int).Sub(amountX, big.NewInt(10))) != 0 || collectY.Cmp(amountY) != 0 {
+		t.Fatalf("collected %s %s not expected", collectX, collectY)
+	}
+	if position, _ = manager.Position(alice); position.Active {
+		t.Fatalf("position should be inactive after everything is collected")
+	}
+	if _, _, err := manager.Collect(alice, limit, limit); err == nil {
+		t.Fatalf("collect of inactive position should fail")
+	}
+}
@@ -0,0 +1,124 @@
+package quoter
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/poolkey"
+	"github.com/izumiFinance/iZiSwap-SDK-go/router"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+const (
+	// boundary points used by the Quoter contract
+	X2YBoundaryPt = -799999
+	Y2XBoundaryPt = 799999
+)
+
+// HopResult is the swap on one pool of the path
+type HopResult struct {
+	TokenIn  string
+	TokenOut string
+	Fee      int
+	// swap called on the pool, Amount is the input amount
+	// or the desired amount passed to it
+	SwapType   swap.SwapType
+	BoundaryPt int
+	Amount     *big.Int
+	AmountIn   *big.Int
+	AmountOut  *big.Int
+	PointAfter int
+	Result     swap.SwapResult
+}
+
+// QuoterResult has the same structure as the return values of
+// Quoter.swapAmount (acquire, pointAfterList) and
+// Quoter.swapDesire (cost, pointAfterList)
+type QuoterResult struct {
+	// acquire of swapAmount, or cost of swapDesire
+	Amount *big.Int
+	// point of each pool after the swap, in the order pools are swapped,
+	// which is the order of path for SwapAmount and the reverse for SwapDesire
+	PointAfterList []int
+	// swap on each pool, in the same order as PointAfterList
+	Hops []HopResult
+}
+
+func swapHop(tokenIn, tokenOut string, fee int, amount *big.Int, desire bool, pool swap.PoolInfo) (HopResult, error) {
+	if pool.Fee != fee {
+		return HopResult{}, fmt.Errorf("fee of pool info %d not match path fee %d", pool.Fee, fee)
+	}
+	key, err := poolkey.New(tokenIn, tokenOut, fee)
+	if err != nil {
+		return HopResult{}, err
+	}
+	var swapType swap.SwapType
+	var boundaryPt int
+	if key.IsTokenX(tokenIn) {
+		swapType, boundaryPt = swap.X2Y, X2YBoundaryPt
+		if desire {
+			swapType = swap.X2YDesireY
+		}
+	} else {
+		swapType, boundaryPt = swap.Y2X, Y2XBoundaryPt
+		if desire {
+			swapType = swap.Y2XDesireX
+		}
+	}
+	swapResult, err := swap.Swap(swapType, amount, boundaryPt, pool)
+	if err != nil {
+		return HopResult{}, err
+	}
+	return HopResult{
+		TokenIn:    tokenIn,
+		TokenOut:   tokenOut,
+		Fee:        fee,
+		SwapType:   swapType,
+		BoundaryPt: boundaryPt,
+		Amount:     new(big.Int).Set(amount),
+		AmountIn:   swapResult.AmountPayed(swapType),
+		AmountOut:  swapResult.AmountAcquired(swapType),
+		PointAfter: swapResult.CurrentPoint,
+		Result:     swapResult,
+	}, nil
+}
+
+// SwapAmount is the offline version of Quoter.swapAmount(amount, path),
+// path is from tokenIn to tokenOut and pools[i] is the state of the i-th pool of path
+func SwapAmount(amount *big.Int, path router.Path, pools []swap.PoolInfo) (QuoterResult, error) {
+	if len(pools) != path.Hops() {
+		return QuoterResult{}, fmt.Errorf("%d pools for path with %d hops", len(pools), path.Hops())
+	}
+	result := QuoterResult{Amount: new(big.Int).Set(amount)}
+	for i := 0; i < path.Hops(); i++ {
+		hop, err := swapHop(path.Tokens[i], path.Tokens[i+1], path.Fees[i], result.Amount, false, pools[i])
+		if err != nil {
+			return QuoterResult{}, err
+		}
+		result.Amount = hop.AmountOut
+		result.PointAfterList = append(result.PointAfterList, hop.PointAfter)
+		result.Hops = append(result.Hops, hop)
+	}
+	return result, nil
+}
+
+// SwapDesire is the offline version of Quoter.swapDesire(desire, path),
+// path is from tokenIn to tokenOut like SwapAmount and router.SwapDesire,
+// the contract takes it reversed, and pools[i] is the state of the i-th pool of path.
+// like the contract, pools are swapped from tokenOut back to tokenIn
+func SwapDesire(desire *big.Int, path router.Path, pools []swap.PoolInfo) (QuoterResult, error) {
+	if len(pools) != path.Hops() {
+		return QuoterResult{}, fmt.Errorf("%d pools for path with %d hops", len(pools), path.Hops())
+	}
+	result := QuoterResult{Amount: new(big.Int).Set(desire)}
+	for i := path.Hops() - 1; i >= 0; i-- {
+		hop, err := swapHop(path.Tokens[i], path.Tokens[i+1], path.Fees[i], result.Amount, true, pools[i])
+		if err != nil {
+			return QuoterResult{}, err
+		}
+		result.Amount = hop.AmountIn
+		result.PointAfterList = append(result.PointAfterList, hop.PointAfter)
+		result.Hops = append(result.Hops, hop)
+	}
+	return result, nil
+}
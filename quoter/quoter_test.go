@@ -0,0 +1,87 @@
+package quoter
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/router"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+const (
+	tokenA = "0x1111111111111111111111111111111111111111"
+	tokenB = "0x2222222222222222222222222222222222222222"
+	tokenC = "0x3333333333333333333333333333333333333333"
+)
+
+func getPoolInfo(fee int) swap.PoolInfo {
+	return swap.PoolInfo{
+		CurrentPoint: 100,
+		PointDelta:   40,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          fee,
+		Liquidity:    big.NewInt(300000),
+		LiquidityX:   big.NewInt(100000),
+		Liquidities: []swap.LiquidityPoint{
+			{LiqudityDelta: big.NewInt(200000), Point: -5000},
+			{LiqudityDelta: big.NewInt(100000), Point: -3000},
+			{LiqudityDelta: big.NewInt(-100000), Point: 3000},
+			{LiqudityDelta: big.NewInt(-200000), Point: 5000},
+		},
+		LimitOrders: []swap.LimitOrderPoint{
+			{SellingY: big.NewInt(100000000000), Point: -1600},
+			{SellingX: big.NewInt(120000000000), Point: 1000},
+		},
+	}
+}
+
+func TestSwapAmount(t *testing.T) {
+	// A => B is x2y on pool (A, B), B => C is x2y on pool (B, C)
+	path, _ := router.NewPath([]string{tokenA, tokenB, tokenC}, []int{2000, 400})
+	pools := []swap.PoolInfo{getPoolInfo(2000), getPoolInfo(400)}
+	amount := big.NewInt(150000000000)
+	result, err := SwapAmount(amount, path, pools)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, _ := swap.SwapX2Y(amount, X2YBoundaryPt, getPoolInfo(2000))
+	second, _ := swap.SwapX2Y(first.AmountY, X2YBoundaryPt, getPoolInfo(400))
+	if result.Amount.Cmp(second.AmountY) != 0 {
+		t.Fatalf("acquire not equal (%s, %s)", result.Amount.String(), second.AmountY.String())
+	}
+	if len(result.PointAfterList) != 2 ||
+		result.PointAfterList[0] != first.CurrentPoint ||
+		result.PointAfterList[1] != second.CurrentPoint {
+		t.Fatalf("pointAfterList not equal (%v, [%d %d])", result.PointAfterList, first.CurrentPoint, second.CurrentPoint)
+	}
+	if result.Hops[1].AmountIn.Cmp(first.AmountY) != 0 {
+		t.Fatalf("amount in of second hop not equal (%s, %s)", result.Hops[1].AmountIn.String(), first.AmountY.String())
+	}
+}
+
+func TestSwapDesire(t *testing.T) {
+	// buy C with A through B, pools are swapped from tokenOut back to tokenIn:
+	// pool (B, C) is x2y desire, then pool (A, B) is x2y desire
+	path, _ := router.NewPath([]string{tokenA, tokenB, tokenC}, []int{2000, 400})
+	pools := []swap.PoolInfo{getPoolInfo(2000), getPoolInfo(400)}
+	desire := big.NewInt(100000000000)
+	result, err := SwapDesire(desire, path, pools)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, _ := swap.SwapX2YDesireY(desire, X2YBoundaryPt, getPoolInfo(400))
+	second, _ := swap.SwapX2YDesireY(first.AmountX, X2YBoundaryPt, getPoolInfo(2000))
+	if result.Amount.Cmp(second.AmountX) != 0 {
+		t.Fatalf("cost not equal (%s, %s)", result.Amount.String(), second.AmountX.String())
+	}
+	if result.PointAfterList[0] != first.CurrentPoint || result.PointAfterList[1] != second.CurrentPoint {
+		t.Fatalf("pointAfterList not equal (%v, [%d %d])", result.PointAfterList, first.CurrentPoint, second.CurrentPoint)
+	}
+	if result.Hops[0].TokenIn != tokenB || result.Hops[0].TokenOut != tokenC || result.Hops[0].Fee != 400 {
+		t.Fatalf("first hop not equal (%s, %s, %d)", result.Hops[0].TokenIn, result.Hops[0].TokenOut, result.Hops[0].Fee)
+	}
+	if _, err = SwapDesire(desire, path, pools[:1]); err == nil {
+		t.Fatalf("expect error for missing pool")
+	}
+}
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+	"github.com/izumiFinance/iZiSwap-SDK-go/poolkey"
+)
+
+const wordSize = 32
+
+var (
+	maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	pow256     = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// selector returns the first 4 bytes of keccak256 of function signature
+func selector(signature string) []byte {
+	return utils.Keccak256([]byte(signature))[:4]
+}
+
+// uintWord encodes an unsigned integer not greater than max in one word
+func uintWord(value *big.Int, max *big.Int) ([]byte, error) {
+	if value == nil || value.Sign() < 0 || value.Cmp(max) > 0 {
+		return nil, fmt.Errorf("value %v out of range [0, %s]", value, max.String())
+	}
+	return value.FillBytes(make([]byte, wordSize)), nil
+}
+
+// intWord encodes a signed integer in one word (two's complement)
+func intWord(value int) []byte {
+	v := big.NewInt(int64(value))
+	if v.Sign() < 0 {
+		v.Add(v, pow256)
+	}
+	return v.FillBytes(make([]byte, wordSize))
+}
+
+func addressWord(address string) ([]byte, error) {
+	bytes, err := poolkey.ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	word := make([]byte, wordSize)
+	copy(word[wordSize-len(bytes):], bytes)
+	return word, nil
+}
+
+// bytesData encodes the length and padded content of dynamic bytes
+func bytesData(data []byte) []byte {
+	paddedLen := (len(data) + wordSize - 1) / wordSize * wordSize
+	encoded := make([]byte, wordSize+paddedLen)
+	big.NewInt(int64(len(data))).FillBytes(encoded[:wordSize])
+	copy(encoded[wordSize:], data)
+	return encoded
+}
+
+func offsetWord(offset int) []byte {
+	return big.NewInt(int64(offset)).FillBytes(make([]byte, wordSize))
+}
+
+func concat(parts ...[]byte) []byte {
+	var result []byte
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
@@ -0,0 +1,228 @@
+package router
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/poolkey"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+var (
+	swapAmountSelector     = selector("swapAmount((bytes,address,uint128,uint256,uint256))")
+	swapDesireSelector     = selector("swapDesire((bytes,address,uint128,uint256,uint256))")
+	swapX2YSelector        = selector("swapX2Y((address,address,uint24,int24,address,uint128,uint256,uint256,uint256))")
+	swapY2XSelector        = selector("swapY2X((address,address,uint24,int24,address,uint128,uint256,uint256,uint256))")
+	swapX2YDesireYSelector = selector("swapX2YDesireY((address,address,uint24,int24,address,uint128,uint256,uint256,uint256))")
+	swapY2XDesireXSelector = selector("swapY2XDesireX((address,address,uint24,int24,address,uint128,uint256,uint256,uint256))")
+	multicallSelector      = selector("multicall(bytes[])")
+	refundETHSelector      = selector("refundETH()")
+	unwrapWETH9Selector    = selector("unwrapWETH9(uint256,address)")
+	sweepTokenSelector     = selector("sweepToken(address,uint256,address)")
+)
+
+// ZeroAddress as recipient makes the router keep acquired token,
+// which is used before unwrapWETH9 or sweepToken in a multicall
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
+// SwapAmountParams is the parameter of Swap.swapAmount
+type SwapAmountParams struct {
+	// path from tokenIn to tokenOut
+	Path        Path
+	Recipient   string
+	Amount      *big.Int
+	MinAcquired *big.Int
+	Deadline    *big.Int
+}
+
+// SwapDesireParams is the parameter of Swap.swapDesire
+type SwapDesireParams struct {
+	// path from tokenIn to tokenOut,
+	// encoded in reverse order (from tokenOut to tokenIn) as the router expects
+	Path      Path
+	Recipient string
+	Desire    *big.Int
+	MaxPayed  *big.Int
+	Deadline  *big.Int
+}
+
+// SwapParams is the parameter of single pool swaps
+// Swap.swapX2Y, swapY2X, swapX2YDesireY and swapY2XDesireX
+type SwapParams struct {
+	TokenX     string
+	TokenY     string
+	Fee        int
+	BoundaryPt int
+	Recipient  string
+	// input amount, or desired amount for desire swaps
+	Amount      *big.Int
+	MaxPayed    *big.Int
+	MinAcquired *big.Int
+	Deadline    *big.Int
+}
+
+// pathCall encodes f((bytes path, address recipient, uint128 amount, uint256 bound, uint256 deadline))
+func pathCall(sel []byte, path Path, recipient string, amount, bound, deadline *big.Int) ([]byte, error) {
+	encodedPath, err := path.Encode()
+	if err != nil {
+		return nil, err
+	}
+	recipientWord, err := addressWord(recipient)
+	if err != nil {
+		return nil, err
+	}
+	amountWord, err := uintWord(amount, maxUint128)
+	if err != nil {
+		return nil, err
+	}
+	boundWord, err := uintWord(bound, maxUint256)
+	if err != nil {
+		return nil, err
+	}
+	deadlineWord, err := uintWord(deadline, maxUint256)
+	if err != nil {
+		return nil, err
+	}
+	return concat(
+		sel,
+		// offset of the tuple
+		offsetWord(wordSize),
+		// offset of path inside the tuple, after 5 head words
+		offsetWord(5*wordSize),
+		recipientWord,
+		amountWord,
+		boundWord,
+		deadlineWord,
+		bytesData(encodedPath),
+	), nil
+}
+
+// SwapAmount encodes calldata of Swap.swapAmount
+func SwapAmount(params SwapAmountParams) ([]byte, error) {
+	return pathCall(swapAmountSelector, params.Path, params.Recipient, params.Amount, params.MinAcquired, params.Deadline)
+}
+
+// SwapDesire encodes calldata of Swap.swapDesire
+func SwapDesire(params SwapDesireParams) ([]byte, error) {
+	return pathCall(swapDesireSelector, params.Path.Reverse(), params.Recipient, params.Desire, params.MaxPayed, params.Deadline)
+}
+
+// Swap encodes calldata of Swap.swapX2Y, swapY2X, swapX2YDesireY
+// or swapY2XDesireX according to swapType
+func Swap(swapType swap.SwapType, params SwapParams) ([]byte, error) {
+	var sel []byte
+	switch swapType {
+	case swap.X2Y:
+		sel = swapX2YSelector
+	case swap.Y2X:
+		sel = swapY2XSelector
+	case swap.X2YDesireY:
+		sel = swapX2YDesireYSelector
+	case swap.Y2XDesireX:
+		sel = swapY2XDesireXSelector
+	default:
+		return nil, fmt.Errorf("unknown swap type %d", swapType)
+	}
+	if params.Fee < 0 || params.Fee >= 1<<24 {
+		return nil, fmt.Errorf("invalid fee %d", params.Fee)
+	}
+	words := [][]byte{sel}
+	for _, address := range []string{params.TokenX, params.TokenY} {
+		word, err := addressWord(address)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	words = append(words, intWord(params.Fee), intWord(params.BoundaryPt))
+	recipientWord, err := addressWord(params.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	words = append(words, recipientWord)
+	amountWord, err := uintWord(params.Amount, maxUint128)
+	if err != nil {
+		return nil, err
+	}
+	words = append(words, amountWord)
+	for _, value := range []*big.Int{params.MaxPayed, params.MinAcquired, params.Deadline} {
+		word, err := uintWord(value, maxUint256)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	return concat(words...), nil
+}
+
+// SwapFromQuote encodes the single pool swap call of a quote,
+// with its boundary point, amount, minAcquired and maxPayed
+func SwapFromQuote(key poolkey.PoolKey, quote swap.SlippageQuote, recipient string, deadline *big.Int) ([]byte, error) {
+	return Swap(quote.SwapType, SwapParams{
+		TokenX:      key.TokenX,
+		TokenY:      key.TokenY,
+		Fee:         key.Fee,
+		BoundaryPt:  quote.BoundaryPt,
+		Recipient:   recipient,
+		Amount:      quote.Amount,
+		MaxPayed:    quote.MaxPayed,
+		MinAcquired: quote.MinAcquired,
+		Deadline:    deadline,
+	})
+}
+
+// Multicall encodes calldata of multicall(bytes[] data)
+func Multicall(calls ...[]byte) []byte {
+	heads := make([][]byte, 0, len(calls))
+	tails := make([][]byte, 0, len(calls))
+	offset := len(calls) * wordSize
+	for _, call := range calls {
+		heads = append(heads, offsetWord(offset))
+		data := bytesData(call)
+		tails = append(tails, data)
+		offset += len(data)
+	}
+	return concat(
+		multicallSelector,
+		offsetWord(wordSize),
+		offsetWord(len(calls)),
+		concat(heads...),
+		concat(tails...),
+	)
+}
+
+// RefundETH encodes calldata of refundETH(), which returns
+// unspent ETH to the sender when paying with native token
+func RefundETH() []byte {
+	return concat(refundETHSelector)
+}
+
+// UnwrapWETH9 encodes calldata of unwrapWETH9(uint256 minAmount, address recipient)
+func UnwrapWETH9(minAmount *big.Int, recipient string) ([]byte, error) {
+	minAmountWord, err := uintWord(minAmount, maxUint256)
+	if err != nil {
+		return nil, err
+	}
+	recipientWord, err := addressWord(recipient)
+	if err != nil {
+		return nil, err
+	}
+	return concat(unwrapWETH9Selector, minAmountWord, recipientWord), nil
+}
+
+// SweepToken encodes calldata of sweepToken(address token, uint256 minAmount, address recipient)
+func SweepToken(token string, minAmount *big.Int, recipient string) ([]byte, error) {
+	tokenWord, err := addressWord(token)
+	if err != nil {
+		return nil, err
+	}
+	minAmountWord, err := uintWord(minAmount, maxUint256)
+	if err != nil {
+		return nil, err
+	}
+	recipientWord, err := addressWord(recipient)
+	if err != nil {
+		return nil, err
+	}
+	return concat(sweepTokenSelector, tokenWord, minAmountWord, recipientWord), nil
+}
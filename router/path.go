@@ -0,0 +1,93 @@
+package router
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/poolkey"
+)
+
+const (
+	addressSize = 20
+	feeSize     = 3
+)
+
+// Path is a multi-hop swap path,
+// Tokens[i] is swapped to Tokens[i+1] in the pool with fee Fees[i]
+type Path struct {
+	Tokens []string
+	Fees   []int
+}
+
+// NewPath checks len(tokens) == len(fees) + 1 and returns the path
+func NewPath(tokens []string, fees []int) (Path, error) {
+	if len(tokens) < 2 || len(tokens) != len(fees)+1 {
+		return Path{}, fmt.Errorf("invalid path, %d tokens and %d fees", len(tokens), len(fees))
+	}
+	return Path{Tokens: tokens, Fees: fees}, nil
+}
+
+// Hops returns number of pools in the path
+func (path Path) Hops() int {
+	return len(path.Fees)
+}
+
+// PoolKey returns key of the i-th pool of the path
+func (path Path) PoolKey(i int) (poolkey.PoolKey, error) {
+	return poolkey.New(path.Tokens[i], path.Tokens[i+1], path.Fees[i])
+}
+
+// Reverse returns the path from last token to first token
+func (path Path) Reverse() Path {
+	tokens := make([]string, len(path.Tokens))
+	fees := make([]int, len(path.Fees))
+	for i, token := range path.Tokens {
+		tokens[len(tokens)-1-i] = token
+	}
+	for i, fee := range path.Fees {
+		fees[len(fees)-1-i] = fee
+	}
+	return Path{Tokens: tokens, Fees: fees}
+}
+
+// Encode packs the path as token(20 bytes) fee(3 bytes) token(20 bytes) ...
+// which is the path format of the Swap router
+func (path Path) Encode() ([]byte, error) {
+	if len(path.Tokens) < 2 || len(path.Tokens) != len(path.Fees)+1 {
+		return nil, fmt.Errorf("invalid path, %d tokens and %d fees", len(path.Tokens), len(path.Fees))
+	}
+	encoded := make([]byte, 0, len(path.Tokens)*addressSize+len(path.Fees)*feeSize)
+	for i, token := range path.Tokens {
+		address, err := poolkey.ParseAddress(token)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, address...)
+		if i < len(path.Fees) {
+			fee := path.Fees[i]
+			if fee < 0 || fee >= 1<<24 {
+				return nil, fmt.Errorf("invalid fee %d", fee)
+			}
+			encoded = append(encoded, byte(fee>>16), byte(fee>>8), byte(fee))
+		}
+	}
+	return encoded, nil
+}
+
+// DecodePath unpacks a path encoded by Path.Encode,
+// tokens are returned as lowercase hex with 0x prefix
+func DecodePath(encoded []byte) (Path, error) {
+	if len(encoded) < 2*addressSize+feeSize || (len(encoded)-addressSize)%(addressSize+feeSize) != 0 {
+		return Path{}, fmt.Errorf("invalid encoded path length %d", len(encoded))
+	}
+	var path Path
+	for offset := 0; ; offset += addressSize + feeSize {
+		path.Tokens = append(path.Tokens, "0x"+hex.EncodeToString(encoded[offset:offset+addressSize]))
+		if offset+addressSize == len(encoded) {
+			break
+		}
+		feeBytes := encoded[offset+addressSize : offset+addressSize+feeSize]
+		path.Fees = append(path.Fees, int(feeBytes[0])<<16|int(feeBytes[1])<<8|int(feeBytes[2]))
+	}
+	return path, nil
+}
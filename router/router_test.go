@@ -0,0 +1,212 @@
+package router
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/poolkey"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+const (
+	tokenA    = "0x1111111111111111111111111111111111111111"
+	tokenB    = "0x2222222222222222222222222222222222222222"
+	tokenC    = "0x3333333333333333333333333333333333333333"
+	recipient = "0x4444444444444444444444444444444444444444"
+)
+
+// word left pads a hex string to 32 bytes
+func word(h string) string {
+	return strings.Repeat("0", 64-len(h)) + h
+}
+
+func checkCalldata(t *testing.T, name string, calldata []byte, expect ...string) {
+	expectHex := strings.Join(expect, "")
+	if hex.EncodeToString(calldata) != expectHex {
+		t.Fatalf("%s calldata not equal\n(%s,\n %s)", name, hex.EncodeToString(calldata), expectHex)
+	}
+}
+
+func TestPath(t *testing.T) {
+	path, err := NewPath([]string{tokenA, tokenB, tokenC}, []int{2000, 400})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, _ := path.Encode()
+	checkCalldata(t, "path", encoded,
+		"1111111111111111111111111111111111111111", "0007d0",
+		"2222222222222222222222222222222222222222", "000190",
+		"3333333333333333333333333333333333333333",
+	)
+	decoded, err := DecodePath(encoded)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range path.Tokens {
+		if decoded.Tokens[i] != path.Tokens[i] {
+			t.Fatalf("token %d not equal (%s, %s)", i, decoded.Tokens[i], path.Tokens[i])
+		}
+	}
+	for i := range path.Fees {
+		if decoded.Fees[i] != path.Fees[i] {
+			t.Fatalf("fee %d not equal (%d, %d)", i, decoded.Fees[i], path.Fees[i])
+		}
+	}
+	if _, err = DecodePath(encoded[:len(encoded)-1]); err == nil {
+		t.Fatalf("expect error for invalid length")
+	}
+}
+
+func TestSwapAmount(t *testing.T) {
+	path, _ := NewPath([]string{tokenA, tokenB, tokenC}, []int{2000, 400})
+	calldata, err := SwapAmount(SwapAmountParams{
+		Path:        path,
+		Recipient:   recipient,
+		Amount:      big.NewInt(1000000),
+		MinAcquired: big.NewInt(990000),
+		Deadline:    big.NewInt(1700000000),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkCalldata(t, "swapAmount", calldata,
+		"75ceafe6",
+		word("20"),
+		word("a0"),
+		word("4444444444444444444444444444444444444444"),
+		word("0f4240"),
+		word("0f1b30"),
+		word("6553f100"),
+		word("42"),
+		"11111111111111111111111111111111111111110007d0222222222222222222",
+		"2222222222222222222222000190333333333333333333333333333333333333",
+		"3333000000000000000000000000000000000000000000000000000000000000",
+	)
+}
+
+func TestSwapDesire(t *testing.T) {
+	path, _ := NewPath([]string{tokenA, tokenB}, []int{10000})
+	calldata, err := SwapDesire(SwapDesireParams{
+		Path:      path,
+		Recipient: recipient,
+		Desire:    big.NewInt(1000000),
+		MaxPayed:  big.NewInt(1010000),
+		Deadline:  big.NewInt(1700000000),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// path is reversed, from tokenB to tokenA
+	checkCalldata(t, "swapDesire", calldata,
+		"115ff67e",
+		word("20"),
+		word("a0"),
+		word("4444444444444444444444444444444444444444"),
+		word("0f4240"),
+		word("0f6950"),
+		word("6553f100"),
+		word("2b"),
+		"2222222222222222222222222222222222222222002710111111111111111111",
+		"1111111111111111111111000000000000000000000000000000000000000000",
+	)
+}
+
+func TestSwapSingleAndMulticall(t *testing.T) {
+	params := SwapParams{
+		TokenX:      tokenA,
+		TokenY:      tokenB,
+		Fee:         2000,
+		BoundaryPt:  -6123,
+		Recipient:   recipient,
+		Amount:      big.NewInt(1000000),
+		MaxPayed:    big.NewInt(1000000),
+		MinAcquired: big.NewInt(990000),
+		Deadline:    big.NewInt(1700000000),
+	}
+	swapCall, err := Swap(swap.X2Y, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	swapWords := []string{
+		word("1111111111111111111111111111111111111111"),
+		word("2222222222222222222222222222222222222222"),
+		word("07d0"),
+		strings.Repeat("f", 60) + "e815",
+		word("4444444444444444444444444444444444444444"),
+		word("0f4240"),
+		word("0f4240"),
+		word("0f1b30"),
+		word("6553f100"),
+	}
+	checkCalldata(t, "swapX2Y", swapCall, append([]string{"46edd9c8"}, swapWords...)...)
+
+	calldata := Multicall(swapCall, RefundETH())
+	expect := []string{
+		"ac9650d8",
+		word("20"),
+		word("2"),
+		// offsets of the two calls
+		word("40"),
+		// 0x40 + 0x20 (length) + 0x140 (9 words + selector, padded)
+		word("1a0"),
+		word("124"),
+		"46edd9c8",
+	}
+	expect = append(expect, swapWords...)
+	expect = append(expect,
+		strings.Repeat("0", 56),
+		word("4"),
+		"12210e8a"+strings.Repeat("0", 56),
+	)
+	checkCalldata(t, "multicall", calldata, expect...)
+
+	params.Amount = new(big.Int).Lsh(big.NewInt(1), 128)
+	if _, err = Swap(swap.X2Y, params); err == nil {
+		t.Fatalf("expect error for amount > uint128.max")
+	}
+}
+
+func TestUnwrapAndSweep(t *testing.T) {
+	calldata, _ := UnwrapWETH9(big.NewInt(1), recipient)
+	checkCalldata(t, "unwrapWETH9", calldata,
+		"49404b7c",
+		word("1"),
+		word("4444444444444444444444444444444444444444"),
+	)
+	calldata, _ = SweepToken(tokenA, big.NewInt(0), recipient)
+	checkCalldata(t, "sweepToken", calldata,
+		"df2ab5bb",
+		word("1111111111111111111111111111111111111111"),
+		word(""),
+		word("4444444444444444444444444444444444444444"),
+	)
+}
+
+func TestSwapFromQuote(t *testing.T) {
+	key, _ := poolkey.New(tokenB, tokenA, 2000)
+	quote := swap.SlippageQuote{
+		SwapType:    swap.Y2XDesireX,
+		Amount:      big.NewInt(1000000),
+		BoundaryPt:  6123,
+		MinAcquired: big.NewInt(1000000),
+		MaxPayed:    big.NewInt(1010000),
+	}
+	calldata, err := SwapFromQuote(key, quote, recipient, big.NewInt(1700000000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkCalldata(t, "swapY2XDesireX", calldata,
+		"826377f6",
+		word("1111111111111111111111111111111111111111"),
+		word("2222222222222222222222222222222222222222"),
+		word("07d0"),
+		word("17eb"),
+		word("4444444444444444444444444444444444444444"),
+		word("0f4240"),
+		word("0f6950"),
+		word("0f4240"),
+		word("6553f100"),
+	)
+}
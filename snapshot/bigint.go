@@ -0,0 +1,49 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// BigInt is a big.Int encoded as a decimal string in json,
+// a json number is also accepted when decoding
+type BigInt struct {
+	big.Int
+}
+
+func NewBigInt(value *big.Int) *BigInt {
+	if value == nil {
+		return nil
+	}
+	bigInt := new(BigInt)
+	bigInt.Set(value)
+	return bigInt
+}
+
+// Value returns a copy of the value, or nil if bigInt is nil
+func (bigInt *BigInt) Value() *big.Int {
+	if bigInt == nil {
+		return nil
+	}
+	return new(big.Int).Set(&bigInt.Int)
+}
+
+func (bigInt BigInt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bigInt.String())
+}
+
+func (bigInt *BigInt) UnmarshalJSON(data []byte) error {
+	var text string
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	} else {
+		text = string(data)
+	}
+	if _, ok := bigInt.SetString(text, 10); !ok {
+		return fmt.Errorf("invalid decimal integer %s", string(data))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package snapshot
+
+import "github.com/izumiFinance/iZiSwap-SDK-go/swap"
+
+// Events is the json form of swap.SwapEvents
+type Events struct {
+	LimitOrders int `json:"limitOrders"`
+	Endpoints   int `json:"endpoints"`
+	BitmapWords int `json:"bitmapWords"`
+	Ranges      int `json:"ranges"`
+}
+
+// SwapResult is the json form of swap.SwapResult
+type SwapResult struct {
+	AmountX      *BigInt `json:"amountX"`
+	AmountY      *BigInt `json:"amountY"`
+	AmountRemain *BigInt `json:"amountRemain"`
+	CurrentPoint int     `json:"currentPoint"`
+	Liquidity    *BigInt `json:"liquidity"`
+	LiquidityX   *BigInt `json:"liquidityX"`
+	StopReason   string  `json:"stopReason"`
+	Events       Events  `json:"events"`
+	// estimated with swap.DefaultGasModel
+	Gas uint64 `json:"gas"`
+}
+
+// TraceStep is the json form of swap.TraceStep
+type TraceStep struct {
+	Kind      string  `json:"kind"`
+	FromPoint int     `json:"fromPoint"`
+	ToPoint   int     `json:"toPoint"`
+	AmountX   *BigInt `json:"amountX,omitempty"`
+	AmountY   *BigInt `json:"amountY,omitempty"`
+	FeeAmount *BigInt `json:"feeAmount,omitempty"`
+	// fee charged by the protocol and fee scale added for liquidity
+	FeeCharged        *BigInt `json:"feeCharged,omitempty"`
+	FeeScaleDelta_128 *BigInt `json:"feeScaleDelta_128,omitempty"`
+	LiquidityDelta    *BigInt `json:"liquidityDelta,omitempty"`
+	Liquidity         *BigInt `json:"liquidity"`
+	LiquidityX        *BigInt `json:"liquidityX"`
+}
+
+func FromSwapResult(result swap.SwapResult) SwapResult {
+	events := result.Events
+	return SwapResult{
+		AmountX:      NewBigInt(result.AmountX),
+		AmountY:      NewBigInt(result.AmountY),
+		AmountRemain: NewBigInt(result.AmountRemain),
+		CurrentPoint: result.CurrentPoint,
+		Liquidity:    NewBigInt(result.Liquidity),
+		LiquidityX:   NewBigInt(result.LiquidityX),
+		StopReason:   result.StopReason.String(),
+		Events: Events{
+			LimitOrders: events.LimitOrders,
+			Endpoints:   events.Endpoints,
+			BitmapWords: events.BitmapWords,
+			Ranges:      events.Ranges,
+		},
+		Gas: result.EstimateGas(swap.DefaultGasModel),
+	}
+}
+
+func FromTrace(trace swap.Trace) []TraceStep {
+	steps := make([]TraceStep, len(trace))
+	for idx, step := range trace {
+		steps[idx] = TraceStep{
+			Kind:              step.Kind.String(),
+			FromPoint:         step.FromPoint,
+			ToPoint:           step.ToPoint,
+			AmountX:           NewBigInt(step.AmountX),
+			AmountY:           NewBigInt(step.AmountY),
+			FeeAmount:         NewBigInt(step.FeeAmount),
+			FeeCharged:        NewBigInt(step.FeeCharged),
+			FeeScaleDelta_128: NewBigInt(step.FeeScaleDelta_128),
+			LiquidityDelta:    NewBigInt(step.LiquidityDelta),
+			Liquidity:         NewBigInt(step.Liquidity),
+			LiquidityX:        NewBigInt(step.LiquidityX),
+		}
+	}
+	return steps
+}
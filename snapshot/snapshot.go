@@ -0,0 +1,182 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Liquidity is a liquidity endpoint, as iZiSwapPool.liquiditySnapshot()
+type Liquidity struct {
+	Point       int     `json:"point"`
+	LiquidDelta *BigInt `json:"liquidDelta"`
+	// optional fee scales outside the point, as points(point) of the pool
+	AccFeeXOut_128 *BigInt `json:"accFeeXOut_128,omitempty"`
+	AccFeeYOut_128 *BigInt `json:"accFeeYOut_128,omitempty"`
+}
+
+// LimitOrder is a point with limit orders, as iZiSwapPool.limitOrderSnapshot()
+type LimitOrder struct {
+	Point    int     `json:"point"`
+	SellingX *BigInt `json:"sellingX,omitempty"`
+	SellingY *BigInt `json:"sellingY,omitempty"`
+	// optional earnings, as limitOrderData(point) of the pool
+	EarnX          *BigInt `json:"earnX,omitempty"`
+	EarnY          *BigInt `json:"earnY,omitempty"`
+	AccEarnX       *BigInt `json:"accEarnX,omitempty"`
+	AccEarnY       *BigInt `json:"accEarnY,omitempty"`
+	LegacyEarnX    *BigInt `json:"legacyEarnX,omitempty"`
+	LegacyEarnY    *BigInt `json:"legacyEarnY,omitempty"`
+	LegacyAccEarnX *BigInt `json:"legacyAccEarnX,omitempty"`
+	LegacyAccEarnY *BigInt `json:"legacyAccEarnY,omitempty"`
+}
+
+// Snapshot is the json form of swap.PoolInfo,
+// big integers are decimal strings
+type Snapshot struct {
+	// optional, where the snapshot is taken
+	Chain string `json:"chain,omitempty"`
+	Pool  string `json:"pool,omitempty"`
+	Block uint64 `json:"block,omitempty"`
+	// optional, addresses of tokenX and tokenY
+	TokenX string `json:"tokenX,omitempty"`
+	TokenY string `json:"tokenY,omitempty"`
+
+	CurrentPoint int          `json:"currentPoint"`
+	PointDelta   int          `json:"pointDelta"`
+	LeftMostPt   int          `json:"leftMostPt"`
+	RightMostPt  int          `json:"rightMostPt"`
+	Fee          int          `json:"fee"`
+	Liquidity    *BigInt      `json:"liquidity"`
+	LiquidityX   *BigInt      `json:"liquidityX"`
+	Liquidities  []Liquidity  `json:"liquidities"`
+	LimitOrders  []LimitOrder `json:"limitOrders"`
+	// optional fee accounting of the pool
+	FeeChargePercent int     `json:"feeChargePercent,omitempty"`
+	FeeScaleX_128    *BigInt `json:"feeScaleX_128,omitempty"`
+	FeeScaleY_128    *BigInt `json:"feeScaleY_128,omitempty"`
+	TotalFeeXCharged *BigInt `json:"totalFeeXCharged,omitempty"`
+	TotalFeeYCharged *BigInt `json:"totalFeeYCharged,omitempty"`
+}
+
+// FromPoolInfo returns snapshot of pool
+func FromPoolInfo(pool swap.PoolInfo) Snapshot {
+	snapshot := Snapshot{
+		CurrentPoint: pool.CurrentPoint,
+		PointDelta:   pool.PointDelta,
+		LeftMostPt:   pool.LeftMostPt,
+		RightMostPt:  pool.RightMostPt,
+		Fee:          pool.Fee,
+		Liquidity:    NewBigInt(pool.Liquidity),
+		LiquidityX:   NewBigInt(pool.LiquidityX),
+		Liquidities:  make([]Liquidity, len(pool.Liquidities)),
+		LimitOrders:  make([]LimitOrder, len(pool.LimitOrders)),
+
+		FeeChargePercent: pool.FeeChargePercent,
+		FeeScaleX_128:    NewBigInt(pool.FeeScaleX_128),
+		FeeScaleY_128:    NewBigInt(pool.FeeScaleY_128),
+		TotalFeeXCharged: NewBigInt(pool.TotalFeeXCharged),
+		TotalFeeYCharged: NewBigInt(pool.TotalFeeYCharged),
+	}
+	for idx, liquidity := range pool.Liquidities {
+		snapshot.Liquidities[idx] = Liquidity{
+			Point:       liquidity.Point,
+			LiquidDelta: NewBigInt(liquidity.LiqudityDelta),
+
+			AccFeeXOut_128: NewBigInt(liquidity.AccFeeXOut_128),
+			AccFeeYOut_128: NewBigInt(liquidity.AccFeeYOut_128),
+		}
+	}
+	for idx, limitOrder := range pool.LimitOrders {
+		snapshot.LimitOrders[idx] = LimitOrder{
+			Point:          limitOrder.Point,
+			SellingX:       NewBigInt(limitOrder.SellingX),
+			SellingY:       NewBigInt(limitOrder.SellingY),
+			EarnX:          NewBigInt(limitOrder.EarnX),
+			EarnY:          NewBigInt(limitOrder.EarnY),
+			AccEarnX:       NewBigInt(limitOrder.AccEarnX),
+			AccEarnY:       NewBigInt(limitOrder.AccEarnY),
+			LegacyEarnX:    NewBigInt(limitOrder.LegacyEarnX),
+			LegacyEarnY:    NewBigInt(limitOrder.LegacyEarnY),
+			LegacyAccEarnX: NewBigInt(limitOrder.LegacyAccEarnX),
+			LegacyAccEarnY: NewBigInt(limitOrder.LegacyAccEarnY),
+		}
+	}
+	return snapshot
+}
+
+// PoolInfo converts snapshot to swap.PoolInfo
+func (snapshot *Snapshot) PoolInfo() (swap.PoolInfo, error) {
+	if snapshot.Liquidity == nil || snapshot.LiquidityX == nil {
+		return swap.PoolInfo{}, fmt.Errorf("liquidity and liquidityX are required")
+	}
+	pool := swap.PoolInfo{
+		CurrentPoint: snapshot.CurrentPoint,
+		PointDelta:   snapshot.PointDelta,
+		LeftMostPt:   snapshot.LeftMostPt,
+		RightMostPt:  snapshot.RightMostPt,
+		Fee:          snapshot.Fee,
+		Liquidity:    snapshot.Liquidity.Value(),
+		LiquidityX:   snapshot.LiquidityX.Value(),
+		Liquidities:  make([]swap.LiquidityPoint, len(snapshot.Liquidities)),
+		LimitOrders:  make([]swap.LimitOrderPoint, len(snapshot.LimitOrders)),
+
+		FeeChargePercent: snapshot.FeeChargePercent,
+		FeeScaleX_128:    snapshot.FeeScaleX_128.Value(),
+		FeeScaleY_128:    snapshot.FeeScaleY_128.Value(),
+		TotalFeeXCharged: snapshot.TotalFeeXCharged.Value(),
+		TotalFeeYCharged: snapshot.TotalFeeYCharged.Value(),
+	}
+	for idx, liquidity := range snapshot.Liquidities {
+		if liquidity.LiquidDelta == nil {
+			return swap.PoolInfo{}, fmt.Errorf("liquidDelta is required at point %d", liquidity.Point)
+		}
+		pool.Liquidities[idx] = swap.LiquidityPoint{
+			LiqudityDelta: liquidity.LiquidDelta.Value(),
+			Point:         liquidity.Point,
+
+			AccFeeXOut_128: liquidity.AccFeeXOut_128.Value(),
+			AccFeeYOut_128: liquidity.AccFeeYOut_128.Value(),
+		}
+	}
+	for idx, limitOrder := range snapshot.LimitOrders {
+		pool.LimitOrders[idx] = swap.LimitOrderPoint{
+			SellingX:       limitOrder.SellingX.Value(),
+			SellingY:       limitOrder.SellingY.Value(),
+			Point:          limitOrder.Point,
+			EarnX:          limitOrder.EarnX.Value(),
+			EarnY:          limitOrder.EarnY.Value(),
+			AccEarnX:       limitOrder.AccEarnX.Value(),
+			AccEarnY:       limitOrder.AccEarnY.Value(),
+			LegacyEarnX:    limitOrder.LegacyEarnX.Value(),
+			LegacyEarnY:    limitOrder.LegacyEarnY.Value(),
+			LegacyAccEarnX: limitOrder.LegacyAccEarnX.Value(),
+			LegacyAccEarnY: limitOrder.LegacyAccEarnY.Value(),
+		}
+	}
+	return pool, nil
+}
+
+// Decode reads a json snapshot from reader
+func Decode(reader io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("decode snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// ReadFile reads a json snapshot from file
+func ReadFile(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Decode(file)
+}
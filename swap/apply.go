@@ -0,0 +1,64 @@
+package swap
+
+import (
+	"math/big"
+	"sort"
+)
+
+// ApplySwap returns the pool after result of a swap on it, as the pool contract
+// stores it: current point, liquidity, liquidityX and limit orders traded in the swap
+// (with earnings) are updated, Bitmap is cloned if limit orders are sold out.
+// fee scales and fee charged of the pool and outside fee scales of crossed endpoints
+// are updated if the result has them. pool is not modified
+func ApplySwap(pool PoolInfo, result SwapResult) PoolInfo {
+	pool.CurrentPoint = result.CurrentPoint
+	pool.Liquidity = new(big.Int).Set(result.Liquidity)
+	pool.LiquidityX = new(big.Int).Set(result.LiquidityX)
+	if result.FeeScaleX_128 != nil && result.FeeScaleY_128 != nil {
+		pool.FeeScaleX_128 = new(big.Int).Set(result.FeeScaleX_128)
+		pool.FeeScaleY_128 = new(big.Int).Set(result.FeeScaleY_128)
+	}
+	if result.FeeXCharged != nil && result.FeeYCharged != nil {
+		pool.TotalFeeXCharged = new(big.Int).Add(copyOrZero(pool.TotalFeeXCharged), result.FeeXCharged)
+		pool.TotalFeeYCharged = new(big.Int).Add(copyOrZero(pool.TotalFeeYCharged), result.FeeYCharged)
+	}
+	if len(result.Endpoints) > 0 {
+		liquidities := make([]LiquidityPoint, len(pool.Liquidities))
+		copy(liquidities, pool.Liquidities)
+		for _, endpoint := range result.Endpoints {
+			if idx, ok := pool.findLiquidity(endpoint.Point); ok {
+				liquidities[idx].AccFeeXOut_128 = new(big.Int).Set(endpoint.AccFeeXOut_128)
+				liquidities[idx].AccFeeYOut_128 = new(big.Int).Set(endpoint.AccFeeYOut_128)
+			}
+		}
+		pool.Liquidities = liquidities
+	}
+	if len(result.LimitOrders) == 0 {
+		return pool
+	}
+
+	limitOrders := make([]LimitOrderPoint, len(pool.LimitOrders))
+	copy(limitOrders, pool.LimitOrders)
+	bitmap := pool.Bitmap
+	if bitmap != nil {
+		bitmap = bitmap.Clone()
+	}
+	for _, limitOrder := range result.LimitOrders {
+		point := limitOrder.Point
+		idx := sort.Search(len(limitOrders), func(i int) bool {
+			return limitOrders[i].Point >= point
+		})
+		if idx == len(limitOrders) || limitOrders[idx].Point != point {
+			// result is not of this pool
+			continue
+		}
+		limitOrders[idx] = limitOrder.clone()
+		if bitmap != nil && !hasSellingX(&limitOrder) && !hasSellingY(&limitOrder) {
+			// point is times of pointDelta as it is in the pool
+			_ = bitmap.SetLimitOrder(point, false)
+		}
+	}
+	pool.LimitOrders = limitOrders
+	pool.Bitmap = bitmap
+	return pool
+}
@@ -0,0 +1,52 @@
+package swap
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestApplySwap(t *testing.T) {
+	pool := limitOrderPool()
+	bitmap, err := BuildPointBitmap(pool)
+	if err != nil {
+		t.Fatalf("build bitmap failed: %v", err)
+	}
+	pool.Bitmap = bitmap
+
+	result, err := SwapX2Y(big.NewInt(500000), -1000, pool)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	after := ApplySwap(pool, result)
+	if after.CurrentPoint != -400 || after.CurrentPoint != result.CurrentPoint {
+		t.Fatalf("current point after swap %d not expected", after.CurrentPoint)
+	}
+	sold := after.LimitOrderAt(0)
+	if sold.SellingY.Sign() != 0 || sold.LegacyEarnX.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("limit order at point 0 not applied: %+v", sold)
+	}
+	if after.Bitmap.IsLimitOrder(0) || !pool.Bitmap.IsLimitOrder(0) {
+		t.Fatalf("bitmap of sold out point should be cleared in the new pool only")
+	}
+	if pool.LimitOrderAt(0).SellingY.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("pool passed in is modified")
+	}
+
+	// next swap continues from the partially sold point
+	partial := after.LimitOrderAt(-400)
+	next, err := SwapX2Y(big.NewInt(100000), -1000, after)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if len(next.LimitOrders) != 1 || next.LimitOrders[0].Point != -400 {
+		t.Fatalf("next swap should trade only point -400: %+v", next.LimitOrders)
+	}
+	if new(big.Int).Add(next.LimitOrders[0].SellingY, next.AmountY).Cmp(partial.SellingY) != 0 {
+		t.Fatalf("tokenY sold in next swap not match")
+	}
+	traded := next.LimitOrders[0]
+	accEarned := new(big.Int).Sub(traded.AccEarnX, partial.AccEarnX)
+	if accEarned.Sign() <= 0 || accEarned.Cmp(new(big.Int).Sub(traded.EarnX, partial.EarnX)) != 0 {
+		t.Fatalf("accumulated earn not continued: %+v", traded)
+	}
+}
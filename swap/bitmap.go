@@ -0,0 +1,199 @@
+package swap
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const (
+	// flags of orderOrEndpoint in the pool contract
+	endpointFlag   = 1
+	limitOrderFlag = 2
+)
+
+// bitmapWord is a 256-bit word of pointBitmap, bit i is in word[i/64]
+type bitmapWord [4]uint64
+
+// highestAtOrBelow returns the highest 1 bit not greater than bit, or -1
+func (word bitmapWord) highestAtOrBelow(bit int) int {
+	for limb := bit / 64; limb >= 0; limb-- {
+		value := word[limb]
+		if limb == bit/64 && bit%64 != 63 {
+			value &= (1 << (bit%64 + 1)) - 1
+		}
+		if value != 0 {
+			return limb*64 + 63 - bits.LeadingZeros64(value)
+		}
+	}
+	return -1
+}
+
+// lowestAtOrAbove returns the lowest 1 bit not less than bit, or -1
+func (word bitmapWord) lowestAtOrAbove(bit int) int {
+	for limb := bit / 64; limb < 4; limb++ {
+		value := word[limb]
+		if limb == bit/64 {
+			value &^= (1 << (bit % 64)) - 1
+		}
+		if value != 0 {
+			return limb*64 + bits.TrailingZeros64(value)
+		}
+	}
+	return -1
+}
+
+// PointBitmap mirrors the pointBitmap and orderOrEndpoint mappings of the pool
+// contract, a point (multiple of pointDelta) is initialized in the bitmap
+// if it is a liquidity endpoint or has a limit order selling tokenX or tokenY.
+// it decides where a swap stops searching, so mint / burn / limit order
+// operations on a pool should update it by SetEndpoint and SetLimitOrder
+type PointBitmap struct {
+	PointDelta int
+	// word index => word
+	words map[int]bitmapWord
+	// point / pointDelta => orderOrEndpoint value
+	orderOrEndpoint map[int]int
+}
+
+func NewPointBitmap(pointDelta int) *PointBitmap {
+	return &PointBitmap{
+		PointDelta:      pointDelta,
+		words:           map[int]bitmapWord{},
+		orderOrEndpoint: map[int]int{},
+	}
+}
+
+// BuildPointBitmap builds the bitmap from liquidities and limit orders of pool
+func BuildPointBitmap(pool PoolInfo) (*PointBitmap, error) {
+	return buildPointBitmap(pool.Liquidities, pool.LimitOrders, pool.PointDelta)
+}
+
+func buildPointBitmap(liquidities []LiquidityPoint, limitOrders []LimitOrderPoint, pointDelta int) (*PointBitmap, error) {
+	bitmap := NewPointBitmap(pointDelta)
+	for _, liquidity := range liquidities {
+		if err := bitmap.SetEndpoint(liquidity.Point, true); err != nil {
+			return nil, err
+		}
+	}
+	for idx := range limitOrders {
+		limitOrder := &limitOrders[idx]
+		if err := bitmap.SetLimitOrder(limitOrder.Point, hasSellingX(limitOrder) || hasSellingY(limitOrder)); err != nil {
+			return nil, err
+		}
+	}
+	return bitmap, nil
+}
+
+// checkBitmap returns an error if Bitmap of pool is not built with
+// pointDelta of pool, a swap would stop at wrong points with it
+func checkBitmap(pool PoolInfo) error {
+	if pool.Bitmap != nil && pool.Bitmap.PointDelta != pool.PointDelta {
+		return fmt.Errorf("pointDelta %d of bitmap not equal to pointDelta %d of pool", pool.Bitmap.PointDelta, pool.PointDelta)
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the bitmap
+func (bitmap *PointBitmap) Clone() *PointBitmap {
+	clone := NewPointBitmap(bitmap.PointDelta)
+	for idx, word := range bitmap.words {
+		clone.words[idx] = word
+	}
+	for mapPt, value := range bitmap.orderOrEndpoint {
+		clone.orderOrEndpoint[mapPt] = value
+	}
+	return clone
+}
+
+func (bitmap *PointBitmap) mapPoint(point int) (int, error) {
+	if point%bitmap.PointDelta != 0 {
+		return 0, fmt.Errorf("point %d is not times of pointDelta %d", point, bitmap.PointDelta)
+	}
+	return point / bitmap.PointDelta, nil
+}
+
+func (bitmap *PointBitmap) setOrderOrEndpoint(point int, flag int, value bool) error {
+	mapPt, err := bitmap.mapPoint(point)
+	if err != nil {
+		return err
+	}
+	val := bitmap.orderOrEndpoint[mapPt]
+	if value {
+		val |= flag
+	} else {
+		val &^= flag
+	}
+	// same as mapPt >> 8 and uint8(mapPt) in the contract
+	wordIdx, bitIdx := mapPt>>8, mapPt&255
+	word := bitmap.words[wordIdx]
+	if val == 0 {
+		delete(bitmap.orderOrEndpoint, mapPt)
+		word[bitIdx/64] &^= 1 << (bitIdx % 64)
+	} else {
+		bitmap.orderOrEndpoint[mapPt] = val
+		word[bitIdx/64] |= 1 << (bitIdx % 64)
+	}
+	if word == (bitmapWord{}) {
+		delete(bitmap.words, wordIdx)
+	} else {
+		bitmap.words[wordIdx] = word
+	}
+	return nil
+}
+
+// SetEndpoint marks or unmarks point as a liquidity endpoint,
+// a point is an endpoint while any liquidity starts or ends there
+func (bitmap *PointBitmap) SetEndpoint(point int, isEndpoint bool) error {
+	return bitmap.setOrderOrEndpoint(point, endpointFlag, isEndpoint)
+}
+
+// SetLimitOrder marks or unmarks point as having limit order,
+// a point has limit order while sellingX or sellingY is not zero
+func (bitmap *PointBitmap) SetLimitOrder(point int, hasLimitOrder bool) error {
+	return bitmap.setOrderOrEndpoint(point, limitOrderFlag, hasLimitOrder)
+}
+
+// IsEndpoint reports whether point is a liquidity endpoint in the bitmap
+func (bitmap *PointBitmap) IsEndpoint(point int) bool {
+	mapPt, err := bitmap.mapPoint(point)
+	return err == nil && bitmap.orderOrEndpoint[mapPt]&endpointFlag != 0
+}
+
+// IsLimitOrder reports whether point has limit order in the bitmap
+func (bitmap *PointBitmap) IsLimitOrder(point int) bool {
+	mapPt, err := bitmap.mapPoint(point)
+	return err == nil && bitmap.orderOrEndpoint[mapPt]&limitOrderFlag != 0
+}
+
+// compressPoint rounds point / pointDelta towards negative infinity
+func (bitmap *PointBitmap) compressPoint(point int) int {
+	mapPt := point / bitmap.PointDelta
+	if point < 0 && point%bitmap.PointDelta != 0 {
+		mapPt-- // round towards negative infinity
+	}
+	return mapPt
+}
+
+// NearestLeftOneOrBoundary returns the nearest initialized point at or on the
+// left of point within the same word, or the left boundary of the word
+func (bitmap *PointBitmap) NearestLeftOneOrBoundary(point int) int {
+	mapPt := bitmap.compressPoint(point)
+	wordIdx, bitIdx := mapPt>>8, mapPt&255
+	msb := bitmap.words[wordIdx].highestAtOrBelow(bitIdx)
+	if msb >= 0 {
+		return (mapPt - (bitIdx - msb)) * bitmap.PointDelta
+	}
+	return (mapPt - bitIdx) * bitmap.PointDelta
+}
+
+// NearestRightOneOrBoundary returns the nearest initialized point on the
+// right of point within the word of the next position, or the right boundary of that word
+func (bitmap *PointBitmap) NearestRightOneOrBoundary(point int) int {
+	mapPt := bitmap.compressPoint(point) + 1
+	wordIdx, bitIdx := mapPt>>8, mapPt&255
+	lsb := bitmap.words[wordIdx].lowestAtOrAbove(bitIdx)
+	if lsb >= 0 {
+		return (mapPt + (lsb - bitIdx)) * bitmap.PointDelta
+	}
+	return (mapPt + (255 - bitIdx)) * bitmap.PointDelta
+}
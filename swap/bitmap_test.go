@@ -0,0 +1,163 @@
+package swap
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestPointBitmapBoundary(t *testing.T) {
+	bitmap := NewPointBitmap(40)
+	// word of point 1887 covers points [0, 255*40]
+	if pt := bitmap.NearestLeftOneOrBoundary(1887); pt != 0 {
+		t.Fatalf("left boundary not equal (%d, %d)", pt, 0)
+	}
+	if pt := bitmap.NearestRightOneOrBoundary(1887); pt != 255*40 {
+		t.Fatalf("right boundary not equal (%d, %d)", pt, 255*40)
+	}
+	// point -1 is mapped to -1, in word covering points [-256*40, -40]
+	if pt := bitmap.NearestLeftOneOrBoundary(-1); pt != -256*40 {
+		t.Fatalf("left boundary not equal (%d, %d)", pt, -256*40)
+	}
+	if pt := bitmap.NearestRightOneOrBoundary(-41); pt != -40 {
+		t.Fatalf("right boundary not equal (%d, %d)", pt, -40)
+	}
+	if pt := bitmap.NearestRightOneOrBoundary(-40); pt != 255*40 {
+		t.Fatalf("right boundary not equal (%d, %d)", pt, 255*40)
+	}
+}
+
+func TestPointBitmapSet(t *testing.T) {
+	bitmap := NewPointBitmap(40)
+	if err := bitmap.SetEndpoint(1200, true); err != nil {
+		t.Fatalf("set endpoint failed: %v", err)
+	}
+	if err := bitmap.SetLimitOrder(1200, true); err != nil {
+		t.Fatalf("set limit order failed: %v", err)
+	}
+	if err := bitmap.SetLimitOrder(-3000, true); err != nil {
+		t.Fatalf("set limit order failed: %v", err)
+	}
+	if err := bitmap.SetEndpoint(1201, true); err == nil {
+		t.Fatalf("point not times of pointDelta should fail")
+	}
+	if pt := bitmap.NearestLeftOneOrBoundary(1887); pt != 1200 {
+		t.Fatalf("nearest left not equal (%d, %d)", pt, 1200)
+	}
+	if pt := bitmap.NearestLeftOneOrBoundary(1200); pt != 1200 {
+		t.Fatalf("nearest left not equal (%d, %d)", pt, 1200)
+	}
+	if pt := bitmap.NearestRightOneOrBoundary(0); pt != 1200 {
+		t.Fatalf("nearest right not equal (%d, %d)", pt, 1200)
+	}
+	if pt := bitmap.NearestRightOneOrBoundary(1200); pt != 255*40 {
+		t.Fatalf("nearest right not equal (%d, %d)", pt, 255*40)
+	}
+	if pt := bitmap.NearestLeftOneOrBoundary(-1000); pt != -3000 {
+		t.Fatalf("nearest left not equal (%d, %d)", pt, -3000)
+	}
+
+	// point stays initialized until both flags are cleared
+	clone := bitmap.Clone()
+	clone.SetEndpoint(1200, false)
+	if !clone.IsLimitOrder(1200) || clone.IsEndpoint(1200) {
+		t.Fatalf("flags of point 1200 not expected")
+	}
+	if pt := clone.NearestLeftOneOrBoundary(1887); pt != 1200 {
+		t.Fatalf("nearest left not equal (%d, %d)", pt, 1200)
+	}
+	clone.SetLimitOrder(1200, false)
+	if pt := clone.NearestLeftOneOrBoundary(1887); pt != 0 {
+		t.Fatalf("nearest left not equal (%d, %d)", pt, 0)
+	}
+	// original bitmap is not changed
+	if !bitmap.IsEndpoint(1200) {
+		t.Fatalf("clone changed original bitmap")
+	}
+}
+
+func TestSwapWithPointBitmap(t *testing.T) {
+	poolInfo := getPoolInfoX2Y()
+	bitmap, err := BuildPointBitmap(poolInfo)
+	if err != nil {
+		t.Fatalf("build bitmap failed: %v", err)
+	}
+	var amount big.Int
+	amount.SetString("100000000000000000000000", 10)
+	expect, _ := SwapX2Y(&amount, -6123, poolInfo)
+	poolInfo.Bitmap = bitmap
+	result, _ := SwapX2Y(&amount, -6123, poolInfo)
+	if result.AmountX.Cmp(expect.AmountX) != 0 || result.AmountY.Cmp(expect.AmountY) != 0 {
+		t.Fatalf("amount not equal (%s, %s), (%s, %s)",
+			result.AmountX.String(), result.AmountY.String(), expect.AmountX.String(), expect.AmountY.String())
+	}
+	if result.CurrentPoint != expect.CurrentPoint {
+		t.Fatalf("current point not equal (%d, %d)", result.CurrentPoint, expect.CurrentPoint)
+	}
+}
+
+// stopPoints returns points where range steps of a swap stop
+func stopPoints(t *testing.T, swapType SwapType, boundary int, pool PoolInfo) []int {
+	_, trace, err := TraceSwap(swapType, new(big.Int).Lsh(big.NewInt(1), 100), boundary, pool)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	var points []int
+	for _, step := range trace {
+		if step.Kind == StepRange {
+			points = append(points, step.ToPoint)
+		}
+	}
+	return points
+}
+
+func TestSwapStopsAtBitmapPoints(t *testing.T) {
+	// a range across several words of the bitmap, a word covers 256 * 40 points
+	pool := PoolInfo{
+		CurrentPoint: 0,
+		PointDelta:   40,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          2000,
+		Liquidity:    big.NewInt(1000000000),
+		LiquidityX:   big.NewInt(0),
+		Liquidities: []LiquidityPoint{
+			{LiqudityDelta: big.NewInt(1000000000), Point: -40000},
+			{LiqudityDelta: big.NewInt(-1000000000), Point: 40000},
+		},
+	}
+	for _, test := range []struct {
+		swapType SwapType
+		boundary int
+		points   []int
+	}{
+		// searches end at word boundaries like the contract
+		{X2Y, -30000, []int{-10240, -20480, -30000}},
+		{Y2X, 30000, []int{10200, 20440, 30000}},
+	} {
+		points := stopPoints(t, test.swapType, test.boundary, pool)
+		if !reflect.DeepEqual(points, test.points) {
+			t.Fatalf("%v stops at %v, expect %v", test.swapType, points, test.points)
+		}
+	}
+
+	// the contract keeps an endpoint with zero delta, known to the
+	// caller maintained bitmap but not to Liquidities
+	bitmap, err := BuildPointBitmap(pool)
+	if err != nil {
+		t.Fatalf("build bitmap failed: %v", err)
+	}
+	bitmap.SetEndpoint(-5000, true)
+	pool.Bitmap = bitmap
+	if points := stopPoints(t, X2Y, -30000, pool); !reflect.DeepEqual(points, []int{-5000, -10240, -20480, -30000}) {
+		t.Fatalf("swap should stop at points of the bitmap, got %v", points)
+	}
+
+	pool.Bitmap = NewPointBitmap(8)
+	if _, err := SwapX2Y(big.NewInt(1000), -30000, pool); err == nil {
+		t.Fatalf("swap with bitmap of another pointDelta should fail")
+	}
+	if err := pool.Validate(); err == nil {
+		t.Fatalf("bitmap of another pointDelta should be invalid")
+	}
+}
@@ -64,6 +64,21 @@ func InitX2Y(liquidities []LiquidityPoint, limitOrders []LimitOrderPoint, curren
 	return orderData
 }
 
+// pointBitmap returns the bitmap driving the search of next point,
+// built once for the swap if not given by the pool,
+// nil if liquidities or limit orders are not on times of pointDelta,
+// in which case next point is searched in liquidities and limit orders directly
+func (orderData *OrderData) pointBitmap(pointDelta int) *PointBitmap {
+	if orderData.Bitmap == nil {
+		bitmap, err := buildPointBitmap(orderData.Liquidities, orderData.LimitOrders, pointDelta)
+		if err != nil {
+			return nil
+		}
+		orderData.Bitmap = bitmap
+	}
+	return orderData.Bitmap
+}
+
 func (orderData *OrderData) findRightPoint(rightBoundary int) int {
 	rightPoint := rightBoundary
 	if orderData.LiquidityIdx < len(orderData.Liquidities) {
@@ -99,6 +114,9 @@ func (orderData *OrderData) MoveY2X(point, pointDelta int) int {
 		idx++
 	}
 	orderData.LimitOrderIdx = idx
+	if bitmap := orderData.pointBitmap(pointDelta); bitmap != nil {
+		return bitmap.NearestRightOneOrBoundary(point)
+	}
 	return orderData.findRightPoint(rightBoundary)
 }
 
@@ -137,17 +155,60 @@ func (orderData *OrderData) MoveX2Y(point, pointDelta int) int {
 		idx--
 	}
 	orderData.LimitOrderIdx = idx
+	if bitmap := orderData.pointBitmap(pointDelta); bitmap != nil {
+		return bitmap.NearestLeftOneOrBoundary(point)
+	}
 	return orderData.findLeftPoint(leftBoundary)
 }
 
+// ConsumeLimitOrder moves to next limit order in the direction of swap,
+// points selling nothing in the direction (kept for earnings) are skipped
 func (orderData *OrderData) ConsumeLimitOrder(isY2X bool) {
 	if isY2X {
 		if orderData.LimitOrderIdx < len(orderData.LimitOrders) {
 			orderData.LimitOrderIdx++
 		}
+		for orderData.LimitOrderIdx < len(orderData.LimitOrders) && !hasSellingX(&orderData.LimitOrders[orderData.LimitOrderIdx]) {
+			orderData.LimitOrderIdx++
+		}
 	} else {
 		if orderData.LimitOrderIdx >= 0 {
 			orderData.LimitOrderIdx--
 		}
+		for orderData.LimitOrderIdx >= 0 && !hasSellingY(&orderData.LimitOrders[orderData.LimitOrderIdx]) {
+			orderData.LimitOrderIdx--
+		}
+	}
+}
+
+// noMoreX2Y reports whether there is no liquidity endpoint on the left of point
+// and no unconsumed limit order selling tokenY at or on the left of point
+func (orderData *OrderData) noMoreX2Y(point int) bool {
+	for idx := orderData.LiquidityIdx; idx >= 0; idx-- {
+		if orderData.Liquidities[idx].Point < point {
+			return false
+		}
+	}
+	for idx := orderData.LimitOrderIdx; idx >= 0; idx-- {
+		if orderData.LimitOrders[idx].Point <= point && hasSellingY(&orderData.LimitOrders[idx]) {
+			return false
+		}
+	}
+	return true
+}
+
+// noMoreY2X reports whether there is no liquidity endpoint on the right of point
+// and no unconsumed limit order selling tokenX at or on the right of point
+func (orderData *OrderData) noMoreY2X(point int) bool {
+	for idx := orderData.LiquidityIdx; idx < len(orderData.Liquidities); idx++ {
+		if orderData.Liquidities[idx].Point > point {
+			return false
+		}
 	}
+	for idx := orderData.LimitOrderIdx; idx < len(orderData.LimitOrders); idx++ {
+		if orderData.LimitOrders[idx].Point >= point && hasSellingX(&orderData.LimitOrders[idx]) {
+			return false
+		}
+	}
+	return true
 }
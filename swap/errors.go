@@ -0,0 +1,41 @@
+package swap
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// DesireNotSatisfiedError is returned by strict desire swaps when the pool
+// runs out of liquidity before the boundary point and can not provide
+// the whole desired amount, in which case the router contract reverts
+type DesireNotSatisfiedError struct {
+	// desired amount of tokenY (x2y) or tokenX (y2x)
+	Desire *big.Int
+	// amount actually acquired from the pool
+	Acquire *big.Int
+	// Desire - Acquire
+	Remain *big.Int
+}
+
+func (err *DesireNotSatisfiedError) Error() string {
+	return fmt.Sprintf("desire not satisfied: desire %s, acquire %s, remain %s",
+		err.Desire.String(), err.Acquire.String(), err.Remain.String())
+}
+
+// SlippageError means a swap result violates minAcquired or maxPayed
+// of a quote, in which case the router contract reverts
+type SlippageError struct {
+	MinAcquired *big.Int
+	Acquired    *big.Int
+	MaxPayed    *big.Int
+	Payed       *big.Int
+}
+
+func (err *SlippageError) Error() string {
+	if err.Acquired.Cmp(err.MinAcquired) < 0 {
+		return fmt.Sprintf("acquire too little: acquired %s, minAcquired %s",
+			err.Acquired.String(), err.MinAcquired.String())
+	}
+	return fmt.Sprintf("pay too much: payed %s, maxPayed %s",
+		err.Payed.String(), err.MaxPayed.String())
+}
@@ -0,0 +1,99 @@
+package swap
+
+import (
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+)
+
+var (
+	pow128 = new(big.Int).Lsh(big.NewInt(1), 128)
+	pow256 = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// subUint256 returns x - y wrapped in uint256 like the assembly sub of the contract
+func subUint256(x, y *big.Int) *big.Int {
+	diff := new(big.Int).Sub(x, y)
+	return diff.Mod(diff, pow256)
+}
+
+// feeScales tracks fee scales of the pool and fee charged by the protocol during a swap
+type feeScales struct {
+	x, y          *big.Int
+	chargePercent int
+	chargedX      *big.Int
+	chargedY      *big.Int
+	evm           calc.EVM
+	// liquidity endpoints crossed, with fee scales outside after crossing
+	endpoints []LiquidityPoint
+}
+
+func newFeeScales(pool PoolInfo) *feeScales {
+	return &feeScales{
+		x:             copyOrZero(pool.FeeScaleX_128),
+		y:             copyOrZero(pool.FeeScaleY_128),
+		chargePercent: pool.FeeChargePercent,
+		chargedX:      big.NewInt(0),
+		chargedY:      big.NewInt(0),
+		evm:           pool.evm(),
+	}
+}
+
+// add charges FeeChargePercent of feeAmount and distributes the rest to liquidity
+// at the current point like the pool contract, fee of limit orders traded
+// without liquidity is charged entirely.
+// it returns fee scale added and fee charged
+func (scales *feeScales) add(feeScale_128, charged, feeAmount, liquidity *big.Int) (*big.Int, *big.Int) {
+	if liquidity.Sign() == 0 {
+		charged.Add(charged, feeAmount)
+		return big.NewInt(0), new(big.Int).Set(feeAmount)
+	}
+	chargedFee := new(big.Int).Mul(feeAmount, big.NewInt(int64(scales.chargePercent)))
+	chargedFee.Div(chargedFee, big.NewInt(100))
+	charged.Add(charged, chargedFee)
+	delta := scales.evm.MulDivFloor(new(big.Int).Sub(feeAmount, chargedFee), pow128, liquidity)
+	feeScale_128.Add(feeScale_128, delta)
+	return delta, chargedFee
+}
+
+// addX distributes fee of tokenX paid in x2y swaps
+func (scales *feeScales) addX(feeAmount, liquidity *big.Int) (*big.Int, *big.Int) {
+	return scales.add(scales.x, scales.chargedX, feeAmount, liquidity)
+}
+
+// addY distributes fee of tokenY paid in y2x swaps
+func (scales *feeScales) addY(feeAmount, liquidity *big.Int) (*big.Int, *big.Int) {
+	return scales.add(scales.y, scales.chargedY, feeAmount, liquidity)
+}
+
+// pass flips fee scales outside endpoint as Point.passEndpoint
+func (scales *feeScales) pass(endpoint LiquidityPoint) {
+	endpoint.AccFeeXOut_128 = subUint256(scales.x, copyOrZero(endpoint.AccFeeXOut_128))
+	endpoint.AccFeeYOut_128 = subUint256(scales.y, copyOrZero(endpoint.AccFeeYOut_128))
+	scales.endpoints = append(scales.endpoints, endpoint)
+}
+
+// FeeScaleInside returns fee scales of tokenX and tokenY earned per 2^-128 liquidity
+// inside [leftPt, rightPt) like the pool contract computes them for a position,
+// values are wrapped in uint256 and only their differences are meaningful
+func (pool PoolInfo) FeeScaleInside(leftPt, rightPt int) (*big.Int, *big.Int) {
+	feeScaleX_128 := copyOrZero(pool.FeeScaleX_128)
+	feeScaleY_128 := copyOrZero(pool.FeeScaleY_128)
+	left := pool.liquidityAt(leftPt)
+	right := pool.liquidityAt(rightPt)
+
+	// fee scales below leftPt
+	lowX, lowY := copyOrZero(left.AccFeeXOut_128), copyOrZero(left.AccFeeYOut_128)
+	if leftPt > pool.CurrentPoint {
+		lowX = subUint256(feeScaleX_128, lowX)
+		lowY = subUint256(feeScaleY_128, lowY)
+	}
+	// fee scales at or above rightPt
+	highX, highY := copyOrZero(right.AccFeeXOut_128), copyOrZero(right.AccFeeYOut_128)
+	if rightPt <= pool.CurrentPoint {
+		highX = subUint256(feeScaleX_128, highX)
+		highY = subUint256(feeScaleY_128, highY)
+	}
+	return subUint256(subUint256(feeScaleX_128, lowX), highX),
+		subUint256(subUint256(feeScaleY_128, lowY), highY)
+}
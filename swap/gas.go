@@ -0,0 +1,48 @@
+package swap
+
+// SwapEvents counts the events of a swap which drive gas cost of the pool contract
+type SwapEvents struct {
+	// limit orders traded with
+	LimitOrders int
+	// liquidity endpoints crossed
+	Endpoints int
+	// words of pointBitmap read when searching the next point, one per search,
+	// a word read again by a later search is counted again
+	BitmapWords int
+	// calls of range computation (x2YRange / y2XRange) in swap math
+	Ranges int
+}
+
+// GasModel is the gas cost of each kind of event in a swap
+type GasModel struct {
+	// fixed cost of a swap, including token transfers and callback
+	Base       uint64
+	LimitOrder uint64
+	Endpoint   uint64
+	BitmapWord uint64
+	Range      uint64
+}
+
+// DefaultGasModel is a rough estimation for a single pool swap
+// through the router, callers may calibrate it for their chain
+var DefaultGasModel = GasModel{
+	Base:       110000,
+	LimitOrder: 22000,
+	Endpoint:   28000,
+	BitmapWord: 2600,
+	Range:      7000,
+}
+
+// Estimate returns the estimated gas of a swap with given events
+func (model GasModel) Estimate(events SwapEvents) uint64 {
+	return model.Base +
+		model.LimitOrder*uint64(events.LimitOrders) +
+		model.Endpoint*uint64(events.Endpoints) +
+		model.BitmapWord*uint64(events.BitmapWords) +
+		model.Range*uint64(events.Ranges)
+}
+
+// EstimateGas returns the estimated gas of the swap under model
+func (swapResult SwapResult) EstimateGas(model GasModel) uint64 {
+	return model.Estimate(swapResult.Events)
+}
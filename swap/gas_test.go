@@ -0,0 +1,31 @@
+package swap
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSwapEvents(t *testing.T) {
+	amount, _ := new(big.Int).SetString("100000000000000000000000", 10)
+	swapResult, _ := SwapX2Y(amount, -6123, getPoolInfoX2Y())
+	// limit orders on 1200, -1000, -3000
+	// endpoints 1000, 800, -800, -1200, -2000, -4000, -5000
+	events := SwapEvents{LimitOrders: 3, Endpoints: 7, BitmapWords: 12, Ranges: 14}
+	if swapResult.Events != events {
+		t.Fatalf("x2y events not equal (%+v, %+v)", swapResult.Events, events)
+	}
+
+	swapResult, _ = SwapY2X(amount, 1100, getPoolInfoY2X())
+	// limit orders on -3000, -1000
+	// endpoints -5000, -4000, -2000, -1200, -800, 800, 1000
+	events = SwapEvents{LimitOrders: 2, Endpoints: 7, BitmapWords: 11, Ranges: 8}
+	if swapResult.Events != events {
+		t.Fatalf("y2x events not equal (%+v, %+v)", swapResult.Events, events)
+	}
+
+	model := GasModel{Base: 100000, LimitOrder: 1000, Endpoint: 100, BitmapWord: 10, Range: 1}
+	gas := uint64(100000 + 2*1000 + 7*100 + 11*10 + 8)
+	if swapResult.EstimateGas(model) != gas {
+		t.Fatalf("gas not equal (%d, %d)", swapResult.EstimateGas(model), gas)
+	}
+}
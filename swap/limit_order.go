@@ -0,0 +1,262 @@
+package swap
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/swapmath"
+)
+
+// AddLimitOrderResult is the result of AddLimitOrderWithX / AddLimitOrderWithY
+type AddLimitOrderResult struct {
+	// amount of token of the order (tokenX for WithX, tokenY for WithY)
+	// resting at the point, orderX / orderY returned by the contract
+	Order *big.Int
+	// part of the order immediately swapped with the opposite limit order
+	// at the point, Cost is in token of the order, Acquire in the other token,
+	// acquireY / acquireX returned by the contract
+	Cost    *big.Int
+	Acquire *big.Int
+	// amount paid by the user, Cost + Order
+	Payed *big.Int
+	// limit orders at the point after the call, with earnings of
+	// the opposite orders updated by the immediate swap
+	LimitOrder LimitOrderPoint
+	// pool after the call, LimitOrders and Bitmap (if not nil) are updated,
+	// other fields are shared with the pool passed in
+	Pool PoolInfo
+}
+
+// AddLimitOrderWithX simulates iZiSwapPool.addLimOrderWithX(recipient, point, amountX),
+// point should be times of pointDelta and in [CurrentPoint, RightMostPt].
+// like the contract, amountX is first swapped with tokenY sold at the point,
+// the order rests only if all tokenY there is bought,
+// liquidity at the current point is never traded.
+// pool is not modified
+func AddLimitOrderWithX(point int, amountX *big.Int, pool PoolInfo) (result AddLimitOrderResult, err error) {
+	defer calc.RecoverRevert(&err)
+	if point%pool.PointDelta != 0 {
+		return AddLimitOrderResult{}, fmt.Errorf("PD")
+	}
+	if point < pool.CurrentPoint {
+		return AddLimitOrderResult{}, fmt.Errorf("PG")
+	}
+	if point > pool.RightMostPt {
+		return AddLimitOrderResult{}, fmt.Errorf("HO")
+	}
+	if amountX.Sign() <= 0 {
+		return AddLimitOrderResult{}, fmt.Errorf("XP")
+	}
+	evm := pool.evm()
+	evm.RequireUint128(amountX)
+
+	limitOrder := findLimitOrder(pool.LimitOrders, point)
+	orderX := new(big.Int).Set(amountX)
+	costX := big.NewInt(0)
+	acquireY := big.NewInt(0)
+	if hasSellingY(&limitOrder) {
+		sqrtPrice_96, _ := calc.GetSqrtPrice(point)
+		costX, acquireY = swapmath.X2YAtPriceEVM(evm, amountX, sqrtPrice_96, limitOrder.SellingY)
+		orderX.Sub(orderX, costX)
+		limitOrder = limitOrder.sellY(evm, costX, acquireY)
+		if limitOrder.SellingY.Sign() > 0 {
+			orderX.SetInt64(0)
+		}
+	}
+	if orderX.Sign() > 0 {
+		limitOrder.SellingX.Add(limitOrder.SellingX, orderX)
+		evm.RequireUint128(limitOrder.SellingX)
+	}
+
+	return addLimitOrderResult(pool, limitOrder, orderX, costX, acquireY)
+}
+
+// AddLimitOrderWithY simulates iZiSwapPool.addLimOrderWithY(recipient, point, amountY),
+// point should be times of pointDelta and in [LeftMostPt, CurrentPoint].
+// like the contract, amountY is first swapped with tokenX sold at the point,
+// the order rests only if all tokenX there is bought,
+// liquidity at the current point is never traded.
+// pool is not modified
+func AddLimitOrderWithY(point int, amountY *big.Int, pool PoolInfo) (result AddLimitOrderResult, err error) {
+	defer calc.RecoverRevert(&err)
+	if point%pool.PointDelta != 0 {
+		return AddLimitOrderResult{}, fmt.Errorf("PD")
+	}
+	if point > pool.CurrentPoint {
+		return AddLimitOrderResult{}, fmt.Errorf("PL")
+	}
+	if point < pool.LeftMostPt {
+		return AddLimitOrderResult{}, fmt.Errorf("LO")
+	}
+	if amountY.Sign() <= 0 {
+		return AddLimitOrderResult{}, fmt.Errorf("YP")
+	}
+	evm := pool.evm()
+	evm.RequireUint128(amountY)
+
+	limitOrder := findLimitOrder(pool.LimitOrders, point)
+	orderY := new(big.Int).Set(amountY)
+	costY := big.NewInt(0)
+	acquireX := big.NewInt(0)
+	if hasSellingX(&limitOrder) {
+		sqrtPrice_96, _ := calc.GetSqrtPrice(point)
+		costY, acquireX = swapmath.Y2XAtPriceEVM(evm, amountY, sqrtPrice_96, limitOrder.SellingX)
+		orderY.Sub(orderY, costY)
+		limitOrder = limitOrder.sellX(evm, costY, acquireX)
+		if limitOrder.SellingX.Sign() > 0 {
+			orderY.SetInt64(0)
+		}
+	}
+	if orderY.Sign() > 0 {
+		limitOrder.SellingY.Add(limitOrder.SellingY, orderY)
+		evm.RequireUint128(limitOrder.SellingY)
+	}
+
+	return addLimitOrderResult(pool, limitOrder, orderY, costY, acquireX)
+}
+
+// findLimitOrder returns a copy of limit orders at point, zero if there is none
+func findLimitOrder(limitOrders []LimitOrderPoint, point int) LimitOrderPoint {
+	idx := sort.Search(len(limitOrders), func(i int) bool {
+		return limitOrders[i].Point >= point
+	})
+	if idx < len(limitOrders) && limitOrders[idx].Point == point {
+		return limitOrders[idx].clone()
+	}
+	return LimitOrderPoint{Point: point}.clone()
+}
+
+func copyOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(value)
+}
+
+// clone returns a deep copy of limitOrder, nil amounts are set to zero
+func (limitOrder LimitOrderPoint) clone() LimitOrderPoint {
+	return LimitOrderPoint{
+		SellingX:       copyOrZero(limitOrder.SellingX),
+		SellingY:       copyOrZero(limitOrder.SellingY),
+		Point:          limitOrder.Point,
+		EarnX:          copyOrZero(limitOrder.EarnX),
+		EarnY:          copyOrZero(limitOrder.EarnY),
+		AccEarnX:       copyOrZero(limitOrder.AccEarnX),
+		AccEarnY:       copyOrZero(limitOrder.AccEarnY),
+		LegacyEarnX:    copyOrZero(limitOrder.LegacyEarnX),
+		LegacyEarnY:    copyOrZero(limitOrder.LegacyEarnY),
+		LegacyAccEarnX: copyOrZero(limitOrder.LegacyAccEarnX),
+		LegacyAccEarnY: copyOrZero(limitOrder.LegacyAccEarnY),
+	}
+}
+
+// sellX returns a copy of limitOrder after acquireX of tokenX sold
+// at the point is bought with costY (fee excluded)
+func (limitOrder LimitOrderPoint) sellX(evm calc.EVM, costY, acquireX *big.Int) LimitOrderPoint {
+	limitOrder = limitOrder.clone()
+	limitOrder.SellingX.Sub(limitOrder.SellingX, acquireX)
+	limitOrder.EarnY.Add(limitOrder.EarnY, costY)
+	limitOrder.AccEarnY.Add(limitOrder.AccEarnY, costY)
+	evm.RequireUint128(limitOrder.EarnY)
+	if limitOrder.SellingX.Sign() == 0 {
+		// all orders selling tokenX are filled
+		limitOrder.LegacyEarnY.Add(limitOrder.LegacyEarnY, limitOrder.EarnY)
+		limitOrder.EarnY.SetInt64(0)
+		limitOrder.LegacyAccEarnY.Set(limitOrder.AccEarnY)
+		evm.RequireUint128(limitOrder.LegacyEarnY)
+	}
+	return limitOrder
+}
+
+// sellY returns a copy of limitOrder after acquireY of tokenY sold
+// at the point is bought with costX (fee excluded)
+func (limitOrder LimitOrderPoint) sellY(evm calc.EVM, costX, acquireY *big.Int) LimitOrderPoint {
+	limitOrder = limitOrder.clone()
+	limitOrder.SellingY.Sub(limitOrder.SellingY, acquireY)
+	limitOrder.EarnX.Add(limitOrder.EarnX, costX)
+	limitOrder.AccEarnX.Add(limitOrder.AccEarnX, costX)
+	evm.RequireUint128(limitOrder.EarnX)
+	if limitOrder.SellingY.Sign() == 0 {
+		// all orders selling tokenY are filled
+		limitOrder.LegacyEarnX.Add(limitOrder.LegacyEarnX, limitOrder.EarnX)
+		limitOrder.EarnX.SetInt64(0)
+		limitOrder.LegacyAccEarnX.Set(limitOrder.AccEarnX)
+		evm.RequireUint128(limitOrder.LegacyEarnX)
+	}
+	return limitOrder
+}
+
+// isEmpty reports whether nothing is sold or ever earned at the point,
+// accumulated earnings are kept like the contract as orders remember them
+func (limitOrder *LimitOrderPoint) isEmpty() bool {
+	for _, value := range []*big.Int{
+		limitOrder.SellingX, limitOrder.SellingY,
+		limitOrder.EarnX, limitOrder.EarnY,
+		limitOrder.AccEarnX, limitOrder.AccEarnY,
+		limitOrder.LegacyEarnX, limitOrder.LegacyEarnY,
+	} {
+		if value != nil && value.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// withLimitOrder returns a copy of limitOrders where the point of limitOrder is
+// replaced by limitOrder, inserted in order, or removed if it is empty
+func withLimitOrder(limitOrders []LimitOrderPoint, limitOrder LimitOrderPoint) []LimitOrderPoint {
+	point := limitOrder.Point
+	idx := sort.Search(len(limitOrders), func(i int) bool {
+		return limitOrders[i].Point >= point
+	})
+	found := idx < len(limitOrders) && limitOrders[idx].Point == point
+	empty := limitOrder.isEmpty()
+
+	updated := make([]LimitOrderPoint, 0, len(limitOrders)+1)
+	updated = append(updated, limitOrders[:idx]...)
+	if !empty {
+		updated = append(updated, limitOrder)
+	}
+	if found {
+		idx++
+	}
+	return append(updated, limitOrders[idx:]...)
+}
+
+// LimitOrderAt returns a copy of limit orders at point, nil amounts are
+// set to zero, a zero LimitOrderPoint is returned if there is none
+func (pool PoolInfo) LimitOrderAt(point int) LimitOrderPoint {
+	return findLimitOrder(pool.LimitOrders, point)
+}
+
+// WithLimitOrder returns a copy of pool where limit orders at limitOrder.Point are
+// replaced by limitOrder, the point is removed if nothing is sold or earned there.
+// Bitmap, if not nil, is cloned and updated. pool is not modified
+func (pool PoolInfo) WithLimitOrder(limitOrder LimitOrderPoint) (PoolInfo, error) {
+	if pool.Bitmap != nil {
+		pool.Bitmap = pool.Bitmap.Clone()
+		hasOrder := hasSellingX(&limitOrder) || hasSellingY(&limitOrder)
+		if err := pool.Bitmap.SetLimitOrder(limitOrder.Point, hasOrder); err != nil {
+			return PoolInfo{}, err
+		}
+	}
+	pool.LimitOrders = withLimitOrder(pool.LimitOrders, limitOrder.clone())
+	return pool, nil
+}
+
+func addLimitOrderResult(pool PoolInfo, limitOrder LimitOrderPoint, order, cost, acquire *big.Int) (AddLimitOrderResult, error) {
+	pool, err := pool.WithLimitOrder(limitOrder)
+	if err != nil {
+		return AddLimitOrderResult{}, err
+	}
+	return AddLimitOrderResult{
+		Order:      order,
+		Cost:       cost,
+		Acquire:    acquire,
+		Payed:      new(big.Int).Add(cost, order),
+		LimitOrder: limitOrder.clone(),
+		Pool:       pool,
+	}, nil
+}
@@ -0,0 +1,193 @@
+package swap
+
+import (
+	"math/big"
+	"testing"
+)
+
+func limitOrderPool() PoolInfo {
+	return PoolInfo{
+		CurrentPoint: 0,
+		PointDelta:   40,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          2000,
+		Liquidity:    big.NewInt(0),
+		LiquidityX:   big.NewInt(0),
+		LimitOrders: []LimitOrderPoint{
+			{Point: -400, SellingX: big.NewInt(0), SellingY: big.NewInt(1000000)},
+			{Point: 0, SellingX: big.NewInt(0), SellingY: big.NewInt(1000)},
+			{Point: 800, SellingX: big.NewInt(500000), SellingY: big.NewInt(0)},
+		},
+	}
+}
+
+func TestAddLimitOrderRest(t *testing.T) {
+	pool := limitOrderPool()
+	bitmap, err := BuildPointBitmap(pool)
+	if err != nil {
+		t.Fatalf("build bitmap failed: %v", err)
+	}
+	pool.Bitmap = bitmap
+
+	result, err := AddLimitOrderWithX(400, big.NewInt(300000), pool)
+	if err != nil {
+		t.Fatalf("add limit order failed: %v", err)
+	}
+	if result.Order.Cmp(big.NewInt(300000)) != 0 || result.Cost.Sign() != 0 || result.Acquire.Sign() != 0 {
+		t.Fatalf("order should rest without swap: %+v", result)
+	}
+	if len(result.Pool.LimitOrders) != 4 || result.Pool.LimitOrders[2].Point != 400 ||
+		result.Pool.LimitOrders[2].SellingX.Cmp(big.NewInt(300000)) != 0 {
+		t.Fatalf("limit order not inserted: %+v", result.Pool.LimitOrders)
+	}
+	if !result.Pool.Bitmap.IsLimitOrder(400) || pool.Bitmap.IsLimitOrder(400) || len(pool.LimitOrders) != 3 {
+		t.Fatalf("bitmap of new pool should be updated, pool passed in should not")
+	}
+
+	// the new order is the first to be bought by y2x
+	swapResult, err := SwapY2X(big.NewInt(1000000000), 600, result.Pool)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if swapResult.AmountX.Cmp(big.NewInt(300000)) != 0 {
+		t.Fatalf("swap acquired %s, expect order 300000", swapResult.AmountX)
+	}
+
+	// add to existing order at point
+	result, err = AddLimitOrderWithY(-400, big.NewInt(5), result.Pool)
+	if err != nil {
+		t.Fatalf("add limit order failed: %v", err)
+	}
+	if result.LimitOrder.SellingY.Cmp(big.NewInt(1000005)) != 0 || len(result.Pool.LimitOrders) != 4 {
+		t.Fatalf("limit order not added to existing one: %+v", result.LimitOrder)
+	}
+}
+
+func TestAddLimitOrderSwap(t *testing.T) {
+	pool := limitOrderPool()
+
+	// tokenY sold at point 0 is bought first, then the rest rests
+	result, err := AddLimitOrderWithX(0, big.NewInt(3000), pool)
+	if err != nil {
+		t.Fatalf("add limit order failed: %v", err)
+	}
+	if result.Acquire.Cmp(big.NewInt(1000)) != 0 || result.Cost.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("swap at price 1 not expected: cost %s acquire %s", result.Cost, result.Acquire)
+	}
+	if result.Order.Cmp(big.NewInt(2000)) != 0 || result.Payed.Cmp(big.NewInt(3000)) != 0 {
+		t.Fatalf("order not expected: order %s payed %s", result.Order, result.Payed)
+	}
+	if result.LimitOrder.SellingY.Sign() != 0 || result.LimitOrder.SellingX.Cmp(big.NewInt(2000)) != 0 {
+		t.Fatalf("limit order at point not expected: %+v", result.LimitOrder)
+	}
+
+	// tokenY is not sold out, nothing rests
+	result, err = AddLimitOrderWithX(0, big.NewInt(300), pool)
+	if err != nil {
+		t.Fatalf("add limit order failed: %v", err)
+	}
+	if result.Order.Sign() != 0 || result.Payed.Cmp(result.Cost) != 0 || result.LimitOrder.SellingY.Cmp(big.NewInt(700)) != 0 {
+		t.Fatalf("partial swap not expected: %+v", result)
+	}
+	if pool.LimitOrders[1].SellingY.Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("pool passed in is modified")
+	}
+
+	// sell out tokenX at point 800 after the price moves there, the point is removed
+	pool.CurrentPoint = 800
+	result, err = AddLimitOrderWithY(800, big.NewInt(1000000), pool)
+	if err != nil {
+		t.Fatalf("add limit order failed: %v", err)
+	}
+	if result.Acquire.Cmp(big.NewInt(500000)) != 0 || result.LimitOrder.SellingX.Sign() != 0 {
+		t.Fatalf("tokenX at point should be sold out: %+v", result)
+	}
+	if result.Order.Sign() <= 0 || result.LimitOrder.SellingY.Cmp(result.Order) != 0 {
+		t.Fatalf("rest of tokenY should rest: %+v", result)
+	}
+}
+
+func TestAddLimitOrderCheck(t *testing.T) {
+	pool := limitOrderPool()
+	for _, test := range []struct {
+		withX bool
+		point int
+		err   string
+	}{
+		{true, 41, "PD"},
+		{true, -40, "PG"},
+		{true, 800040, "HO"},
+		{false, 40, "PL"},
+		{false, -800040, "LO"},
+	} {
+		var err error
+		if test.withX {
+			_, err = AddLimitOrderWithX(test.point, big.NewInt(1), pool)
+		} else {
+			_, err = AddLimitOrderWithY(test.point, big.NewInt(1), pool)
+		}
+		if err == nil || err.Error() != test.err {
+			t.Fatalf("add limit order at %d should fail with %s, got %v", test.point, test.err, err)
+		}
+	}
+	if _, err := AddLimitOrderWithX(40, big.NewInt(0), pool); err == nil || err.Error() != "XP" {
+		t.Fatalf("zero amount should fail with XP, got %v", err)
+	}
+	if _, err := AddLimitOrderWithY(-40, big.NewInt(0), pool); err == nil || err.Error() != "YP" {
+		t.Fatalf("zero amount should fail with YP, got %v", err)
+	}
+}
+
+func TestSwapLimitOrderEarn(t *testing.T) {
+	pool := limitOrderPool()
+	pool.LimitOrders[0].EarnX = big.NewInt(7)
+	pool.LimitOrders[0].AccEarnX = big.NewInt(10)
+	// sold out before, kept for earnings and skipped by swaps
+	pool.LimitOrders = append([]LimitOrderPoint{{
+		Point:          -800,
+		SellingY:       big.NewInt(0),
+		AccEarnX:       big.NewInt(30),
+		LegacyEarnX:    big.NewInt(30),
+		LegacyAccEarnX: big.NewInt(30),
+	}}, pool.LimitOrders...)
+
+	result, trace, err := TraceSwap(X2Y, big.NewInt(500000), -1000, pool)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if result.Events.LimitOrders != 2 || len(result.LimitOrders) != 2 {
+		t.Fatalf("swap should trade 2 limit orders, %d traded", result.Events.LimitOrders)
+	}
+
+	// tokenY at point 0 is sold out, earnings become legacy
+	sold := result.LimitOrders[0]
+	if sold.Point != 0 || sold.SellingY.Sign() != 0 || sold.EarnX.Sign() != 0 ||
+		sold.LegacyEarnX.Cmp(big.NewInt(1000)) != 0 || sold.LegacyAccEarnX.Cmp(sold.AccEarnX) != 0 {
+		t.Fatalf("limit order at point 0 not expected: %+v", sold)
+	}
+	// tokenY at point -400 is partially sold
+	partial := result.LimitOrders[1]
+	costX := new(big.Int).Sub(partial.EarnX, big.NewInt(7))
+	if partial.Point != -400 || partial.SellingY.Sign() <= 0 || costX.Sign() <= 0 ||
+		new(big.Int).Sub(partial.AccEarnX, costX).Cmp(big.NewInt(10)) != 0 || partial.LegacyEarnX.Sign() != 0 {
+		t.Fatalf("limit order at point -400 not expected: %+v", partial)
+	}
+	if new(big.Int).Add(partial.SellingY, result.AmountY).Cmp(big.NewInt(1001000)) != 0 {
+		t.Fatalf("sold tokenY %s not match acquired %s", partial.SellingY, result.AmountY)
+	}
+
+	// tokenX paid is earned by limit orders or charged as fee
+	paid := new(big.Int).Add(sold.LegacyEarnX, costX)
+	for _, step := range trace {
+		if step.FeeAmount != nil {
+			paid.Add(paid, step.FeeAmount)
+		}
+	}
+	if paid.Cmp(result.AmountX) != 0 {
+		t.Fatalf("earned and fee %s not equal to paid %s", paid, result.AmountX)
+	}
+	if pool.LimitOrders[1].EarnX.Cmp(big.NewInt(7)) != 0 {
+		t.Fatalf("pool passed in is modified")
+	}
+}
@@ -0,0 +1,199 @@
+package swap
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/amountmath"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/utils"
+)
+
+// LiquidityResult is the result of Mint / Burn
+type LiquidityResult struct {
+	// tokenX and tokenY deposited by Mint or withdrawn by Burn,
+	// amounts burned are owed to the position until collected
+	AmountX *big.Int
+	AmountY *big.Int
+	// fee scales inside [leftPt, rightPt) after the call (accFeeXIn_128, accFeeYIn_128),
+	// a position of the range earns (AccFeeXIn_128 - its last fee scale) * liquidity / 2^128
+	AccFeeXIn_128 *big.Int
+	AccFeeYIn_128 *big.Int
+	// pool after the call, Liquidities, Liquidity, LiquidityX and Bitmap (if not nil)
+	// are updated, other fields are shared with the pool passed in
+	Pool PoolInfo
+}
+
+// Mint simulates iZiSwapPool.mint(recipient, leftPt, rightPt, liquidDelta),
+// leftPt and rightPt should be times of pointDelta in [LeftMostPt, RightMostPt].
+// liquidity at the current point is deposited as tokenY like the contract.
+// pool is not modified
+func Mint(leftPt, rightPt int, liquidDelta *big.Int, pool PoolInfo) (result LiquidityResult, err error) {
+	defer calc.RecoverRevert(&err)
+	evm := pool.evm()
+	if err := checkRange(leftPt, rightPt, pool); err != nil {
+		return LiquidityResult{}, err
+	}
+	if liquidDelta.Sign() <= 0 {
+		return LiquidityResult{}, fmt.Errorf("LP")
+	}
+	evm.RequireUint128(liquidDelta)
+
+	pool = pool.withLiquidityDelta(leftPt, liquidDelta)
+	pool = pool.withLiquidityDelta(rightPt, new(big.Int).Neg(liquidDelta))
+
+	amountX, amountY := big.NewInt(0), big.NewInt(0)
+	sqrtRate_96, _ := calc.GetSqrtPrice(1)
+	sqrtPriceR_96, _ := calc.GetSqrtPrice(rightPt)
+	if leftPt < pool.CurrentPoint {
+		sqrtPriceL_96, _ := calc.GetSqrtPrice(leftPt)
+		sqrtPriceM_96, _ := calc.GetSqrtPrice(calc.Min(rightPt, pool.CurrentPoint))
+		amountY.Add(amountY, amountmath.GetAmountYEVM(evm, liquidDelta, sqrtPriceL_96, sqrtPriceM_96, sqrtRate_96, true))
+	}
+	if rightPt > pool.CurrentPoint {
+		xrLeft := calc.Max(leftPt, pool.CurrentPoint+1)
+		amountX.Add(amountX, amountmath.GetAmountXEVM(evm, liquidDelta, xrLeft, rightPt, sqrtPriceR_96, sqrtRate_96, true))
+	}
+	if leftPt <= pool.CurrentPoint && rightPt > pool.CurrentPoint {
+		sqrtPrice_96, _ := calc.GetSqrtPrice(pool.CurrentPoint)
+		amountY.Add(amountY, evm.MulDivCeil(liquidDelta, sqrtPrice_96, utils.Pow96))
+		pool.Liquidity = evm.AddDelta(copyOrZero(pool.Liquidity), liquidDelta)
+	}
+	evm.RequireUint128(amountX, amountY)
+
+	return liquidityResult(leftPt, rightPt, amountX, amountY, pool), nil
+}
+
+// Burn simulates iZiSwapPool.burn(leftPt, rightPt, liquidDelta), the range should
+// have been minted. at the current point, tokenY liquidity is withdrawn first,
+// then tokenX liquidity (LiquidityX). endpoints are kept with zero delta as
+// liquidity of other positions there is unknown. pool is not modified
+func Burn(leftPt, rightPt int, liquidDelta *big.Int, pool PoolInfo) (result LiquidityResult, err error) {
+	defer calc.RecoverRevert(&err)
+	evm := pool.evm()
+	if err := checkRange(leftPt, rightPt, pool); err != nil {
+		return LiquidityResult{}, err
+	}
+	if liquidDelta.Sign() < 0 {
+		return LiquidityResult{}, fmt.Errorf("negative liquidity %s", liquidDelta)
+	}
+	evm.RequireUint128(liquidDelta)
+	for _, point := range []int{leftPt, rightPt} {
+		if _, ok := pool.findLiquidity(point); !ok {
+			return LiquidityResult{}, fmt.Errorf("point %d is not a liquidity endpoint", point)
+		}
+	}
+
+	amountX, amountY := big.NewInt(0), big.NewInt(0)
+	if liquidDelta.Sign() == 0 {
+		// only fees are updated
+		return liquidityResult(leftPt, rightPt, amountX, amountY, pool), nil
+	}
+	pool = pool.withLiquidityDelta(leftPt, new(big.Int).Neg(liquidDelta))
+	pool = pool.withLiquidityDelta(rightPt, liquidDelta)
+
+	sqrtRate_96, _ := calc.GetSqrtPrice(1)
+	sqrtPriceR_96, _ := calc.GetSqrtPrice(rightPt)
+	if leftPt < pool.CurrentPoint {
+		sqrtPriceL_96, _ := calc.GetSqrtPrice(leftPt)
+		sqrtPriceM_96, _ := calc.GetSqrtPrice(calc.Min(rightPt, pool.CurrentPoint))
+		amountY.Add(amountY, amountmath.GetAmountYEVM(evm, liquidDelta, sqrtPriceL_96, sqrtPriceM_96, sqrtRate_96, false))
+	}
+	if rightPt > pool.CurrentPoint {
+		xrLeft := calc.Max(leftPt, pool.CurrentPoint+1)
+		amountX.Add(amountX, amountmath.GetAmountXEVM(evm, liquidDelta, xrLeft, rightPt, sqrtPriceR_96, sqrtRate_96, false))
+	}
+	if leftPt <= pool.CurrentPoint && rightPt > pool.CurrentPoint {
+		sqrtPrice_96, _ := calc.GetSqrtPrice(pool.CurrentPoint)
+		liquidity := copyOrZero(pool.Liquidity)
+		liquidityX := copyOrZero(pool.LiquidityX)
+		liquidityY := new(big.Int).Sub(liquidity, liquidityX)
+		withdrawX := big.NewInt(0)
+		if liquidDelta.Cmp(liquidityY) > 0 {
+			withdrawX.Sub(liquidDelta, liquidityY)
+		}
+		withdrawY := new(big.Int).Sub(liquidDelta, withdrawX)
+		amountX.Add(amountX, evm.MulDivFloor(withdrawX, utils.Pow96, sqrtPrice_96))
+		amountY.Add(amountY, evm.MulDivFloor(withdrawY, sqrtPrice_96, utils.Pow96))
+		pool.Liquidity = evm.AddDelta(liquidity, new(big.Int).Neg(liquidDelta))
+		pool.LiquidityX = evm.AddDelta(liquidityX, new(big.Int).Neg(withdrawX))
+	}
+
+	return liquidityResult(leftPt, rightPt, amountX, amountY, pool), nil
+}
+
+func checkRange(leftPt, rightPt int, pool PoolInfo) error {
+	if leftPt >= rightPt {
+		return fmt.Errorf("LR")
+	}
+	if leftPt < pool.LeftMostPt {
+		return fmt.Errorf("LO")
+	}
+	if rightPt > pool.RightMostPt {
+		return fmt.Errorf("HO")
+	}
+	if leftPt%pool.PointDelta != 0 {
+		return fmt.Errorf("LPD")
+	}
+	if rightPt%pool.PointDelta != 0 {
+		return fmt.Errorf("RPD")
+	}
+	return nil
+}
+
+func liquidityResult(leftPt, rightPt int, amountX, amountY *big.Int, pool PoolInfo) LiquidityResult {
+	accFeeXIn_128, accFeeYIn_128 := pool.FeeScaleInside(leftPt, rightPt)
+	return LiquidityResult{
+		AmountX:       amountX,
+		AmountY:       amountY,
+		AccFeeXIn_128: accFeeXIn_128,
+		AccFeeYIn_128: accFeeYIn_128,
+		Pool:          pool,
+	}
+}
+
+func (pool PoolInfo) findLiquidity(point int) (int, bool) {
+	idx := sort.Search(len(pool.Liquidities), func(i int) bool {
+		return pool.Liquidities[i].Point >= point
+	})
+	return idx, idx < len(pool.Liquidities) && pool.Liquidities[idx].Point == point
+}
+
+// liquidityAt returns liquidity point at point, a zero LiquidityPoint if there is none
+func (pool PoolInfo) liquidityAt(point int) LiquidityPoint {
+	if idx, ok := pool.findLiquidity(point); ok {
+		return pool.Liquidities[idx]
+	}
+	return LiquidityPoint{Point: point, LiqudityDelta: big.NewInt(0)}
+}
+
+// withLiquidityDelta returns a copy of pool with delta added at point, a new endpoint
+// is inserted with fee scales outside initialized like Point.updateEndpoint
+// and marked in Bitmap (cloned) if not nil
+func (pool PoolInfo) withLiquidityDelta(point int, delta *big.Int) PoolInfo {
+	idx, found := pool.findLiquidity(point)
+	liquidities := make([]LiquidityPoint, 0, len(pool.Liquidities)+1)
+	liquidities = append(liquidities, pool.Liquidities[:idx]...)
+	if found {
+		updated := pool.Liquidities[idx]
+		updated.LiqudityDelta = new(big.Int).Add(updated.LiqudityDelta, delta)
+		pool.evm().RequireInt128(updated.LiqudityDelta)
+		liquidities = append(liquidities, updated)
+		idx++
+	} else {
+		endpoint := LiquidityPoint{Point: point, LiqudityDelta: new(big.Int).Set(delta)}
+		if point <= pool.CurrentPoint {
+			endpoint.AccFeeXOut_128 = copyOrZero(pool.FeeScaleX_128)
+			endpoint.AccFeeYOut_128 = copyOrZero(pool.FeeScaleY_128)
+		}
+		liquidities = append(liquidities, endpoint)
+		if pool.Bitmap != nil {
+			pool.Bitmap = pool.Bitmap.Clone()
+			// point is checked to be times of pointDelta
+			_ = pool.Bitmap.SetEndpoint(point, true)
+		}
+	}
+	pool.Liquidities = append(liquidities, pool.Liquidities[idx:]...)
+	return pool
+}
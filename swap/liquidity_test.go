@@ -0,0 +1,216 @@
+package swap
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+)
+
+func liquidityPool() PoolInfo {
+	return PoolInfo{
+		CurrentPoint: 0,
+		PointDelta:   40,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          2000,
+		Liquidity:    big.NewInt(0),
+		LiquidityX:   big.NewInt(0),
+	}
+}
+
+func TestMintBurn(t *testing.T) {
+	pool := liquidityPool()
+	bitmap, err := BuildPointBitmap(pool)
+	if err != nil {
+		t.Fatalf("build bitmap failed: %v", err)
+	}
+	pool.Bitmap = bitmap
+
+	result, err := Mint(-400, 400, big.NewInt(1000000), pool)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	after := result.Pool
+	if after.Liquidity.Cmp(big.NewInt(1000000)) != 0 || after.LiquidityX.Sign() != 0 {
+		t.Fatalf("liquidity at current point not expected: %s %s", after.Liquidity, after.LiquidityX)
+	}
+	if len(after.Liquidities) != 2 || !after.Bitmap.IsEndpoint(-400) || pool.Bitmap.IsEndpoint(-400) || len(pool.Liquidities) != 0 {
+		t.Fatalf("endpoints should be added to the new pool only: %+v", after.Liquidities)
+	}
+	// price is about 1, 1000000 of tokenY at each point of [-400, 0]
+	// and 1000000 of tokenX at each point of [40, 400)
+	if result.AmountX.Cmp(big.NewInt(390000000)) < 0 || result.AmountX.Cmp(big.NewInt(400000000)) > 0 ||
+		result.AmountY.Cmp(big.NewInt(390000000)) < 0 || result.AmountY.Cmp(big.NewInt(400000000)) > 0 {
+		t.Fatalf("deposit not expected: %s %s", result.AmountX, result.AmountY)
+	}
+
+	burn, err := Burn(-400, 400, big.NewInt(1000000), after)
+	if err != nil {
+		t.Fatalf("burn failed: %v", err)
+	}
+	if burn.Pool.Liquidity.Sign() != 0 || burn.Pool.Liquidities[0].LiqudityDelta.Sign() != 0 {
+		t.Fatalf("liquidity should be burned: %+v", burn.Pool.Liquidities)
+	}
+	for _, diff := range []*big.Int{
+		new(big.Int).Sub(result.AmountX, burn.AmountX),
+		new(big.Int).Sub(result.AmountY, burn.AmountY),
+	} {
+		if diff.Sign() < 0 || diff.Cmp(big.NewInt(2)) > 0 {
+			t.Fatalf("burn should withdraw the deposit rounded down: %s %s", burn.AmountX, burn.AmountY)
+		}
+	}
+
+	for _, test := range []struct {
+		leftPt, rightPt int
+		err             string
+	}{
+		{400, 400, "LR"},
+		{-800040, 0, "LO"},
+		{0, 800040, "HO"},
+		{-41, 0, "LPD"},
+		{0, 41, "RPD"},
+	} {
+		if _, err := Mint(test.leftPt, test.rightPt, big.NewInt(1), pool); err == nil || err.Error() != test.err {
+			t.Fatalf("mint [%d, %d) should fail with %s, got %v", test.leftPt, test.rightPt, test.err, err)
+		}
+	}
+	if _, err := Mint(-400, 400, big.NewInt(0), pool); err == nil || err.Error() != "LP" {
+		t.Fatalf("zero liquidity should fail with LP, got %v", err)
+	}
+	if _, err := Burn(-400, 400, big.NewInt(1), pool); err == nil {
+		t.Fatalf("burn without endpoints should fail")
+	}
+}
+
+func TestSwapFeeScale(t *testing.T) {
+	pool := liquidityPool()
+	inner, err := Mint(-400, 400, big.NewInt(1000000), pool)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	outer, err := Mint(-4000, -400, big.NewInt(3000000), inner.Pool)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	pool = outer.Pool
+
+	result, trace, err := TraceSwap(X2Y, big.NewInt(600000000), -4000, pool)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	if result.CurrentPoint >= -400 || len(result.Endpoints) != 1 || result.Endpoints[0].Point != -400 {
+		t.Fatalf("swap should cross -400: point %d endpoints %+v", result.CurrentPoint, result.Endpoints)
+	}
+
+	// fee of each step is distributed to its liquidity
+	expect := big.NewInt(0)
+	innerFee := big.NewInt(0)
+	for _, step := range trace {
+		if step.Kind != StepRange {
+			continue
+		}
+		expect.Add(expect, calc.MulDivFloor(step.FeeAmount, pow128, step.Liquidity))
+		if step.FromPoint >= -400 {
+			innerFee.Set(expect)
+		}
+	}
+	if result.FeeScaleX_128.Cmp(expect) != 0 || result.FeeScaleY_128.Sign() != 0 {
+		t.Fatalf("fee scale %s not expected %s", result.FeeScaleX_128, expect)
+	}
+	if result.Endpoints[0].AccFeeXOut_128.Cmp(innerFee) != 0 {
+		t.Fatalf("fee scale outside -400 %s, expect %s", result.Endpoints[0].AccFeeXOut_128, innerFee)
+	}
+
+	after := ApplySwap(pool, result)
+	if after.FeeScaleX_128.Cmp(expect) != 0 || pool.FeeScaleX_128 != nil {
+		t.Fatalf("fee scale not applied")
+	}
+	insideX, insideY := after.FeeScaleInside(-400, 400)
+	if insideX.Cmp(innerFee) != 0 || insideY.Sign() != 0 {
+		t.Fatalf("fee scale inside [-400, 400) %s, expect %s", insideX, innerFee)
+	}
+	insideX, _ = after.FeeScaleInside(-4000, -400)
+	if insideX.Cmp(new(big.Int).Sub(expect, innerFee)) != 0 {
+		t.Fatalf("fee scale inside [-4000, -400) %s not expected", insideX)
+	}
+	if insideX, _ = after.FeeScaleInside(400, 800); insideX.Sign() != 0 {
+		t.Fatalf("range out of the swap should earn nothing, got %s", insideX)
+	}
+
+	// crossing back flips the endpoint again
+	back, err := SwapY2X(big.NewInt(1000000000), 1000, after)
+	if err != nil {
+		t.Fatalf("swap failed: %v", err)
+	}
+	after = ApplySwap(after, back)
+	insideX, insideY = after.FeeScaleInside(-400, 400)
+	if insideX.Cmp(innerFee) != 0 || insideY.Sign() <= 0 {
+		t.Fatalf("fee scale inside [-400, 400) after swap back not expected: %s %s", insideX, insideY)
+	}
+}
+
+func TestSwapFeeCharge(t *testing.T) {
+	pool := liquidityPool()
+	pool.FeeChargePercent = 20
+	minted, err := Mint(-400, 400, big.NewInt(1000000), pool)
+	if err != nil {
+		t.Fatalf("mint failed: %v", err)
+	}
+	pool = minted.Pool
+	// traded without liquidity
+	pool.LimitOrders = []LimitOrderPoint{
+		{Point: -800, SellingX: big.NewInt(0), SellingY: big.NewInt(100000)},
+		{Point: 800, SellingX: big.NewInt(100000), SellingY: big.NewInt(0)},
+	}
+
+	for _, test := range []struct {
+		swapType SwapType
+		amount   int64
+		boundary int
+	}{
+		{X2Y, 1000000000, -1000},
+		{X2YDesireY, 400000000, -1000},
+		{Y2X, 1000000000, 1000},
+		{Y2XDesireX, 400000000, 1000},
+	} {
+		result, trace, err := TraceSwap(test.swapType, big.NewInt(test.amount), test.boundary, pool)
+		if err != nil {
+			t.Fatalf("swap %v failed: %v", test.swapType, err)
+		}
+		if result.Events.LimitOrders != 1 {
+			t.Fatalf("swap %v should trade the limit order out of liquidity", test.swapType)
+		}
+		feeScale := big.NewInt(0)
+		charged := big.NewInt(0)
+		for _, step := range trace {
+			if step.FeeAmount == nil {
+				continue
+			}
+			if step.Liquidity.Sign() == 0 {
+				charged.Add(charged, step.FeeAmount)
+				continue
+			}
+			chargedFee := new(big.Int).Div(new(big.Int).Mul(step.FeeAmount, big.NewInt(20)), big.NewInt(100))
+			charged.Add(charged, chargedFee)
+			feeScale.Add(feeScale, calc.MulDivFloor(new(big.Int).Sub(step.FeeAmount, chargedFee), pow128, step.Liquidity))
+		}
+
+		resultScale, resultCharged, otherCharged := result.FeeScaleX_128, result.FeeXCharged, result.FeeYCharged
+		if test.swapType == Y2X || test.swapType == Y2XDesireX {
+			resultScale, resultCharged, otherCharged = result.FeeScaleY_128, result.FeeYCharged, result.FeeXCharged
+		}
+		if resultScale.Cmp(feeScale) != 0 || resultCharged.Cmp(charged) != 0 || otherCharged.Sign() != 0 {
+			t.Fatalf("swap %v fee scale %s charged %s, expect %s %s", test.swapType, resultScale, resultCharged, feeScale, charged)
+		}
+		if charged.Sign() <= 0 || feeScale.Sign() <= 0 {
+			t.Fatalf("swap %v should both charge and distribute fee", test.swapType)
+		}
+
+		after := ApplySwap(ApplySwap(pool, result), result)
+		if after.TotalFeeXCharged.Cmp(new(big.Int).Mul(result.FeeXCharged, big.NewInt(2))) != 0 ||
+			after.TotalFeeYCharged.Cmp(new(big.Int).Mul(result.FeeYCharged, big.NewInt(2))) != 0 {
+			t.Fatalf("charged fee should accumulate in the pool")
+		}
+	}
+}
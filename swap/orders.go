@@ -5,12 +5,35 @@ import "math/big"
 type LiquidityPoint struct {
 	LiqudityDelta *big.Int
 	Point         int
+
+	// optional fee scales outside the point as the pool contract keeps them
+	// in points (accFeeXOut_128, accFeeYOut_128), nil means zero.
+	// they are fee scales on the other side of the point from the current point,
+	// swaps flip them when the point is crossed
+	AccFeeXOut_128 *big.Int
+	AccFeeYOut_128 *big.Int
 }
 
 type LimitOrderPoint struct {
 	SellingX *big.Int
 	SellingY *big.Int
 	Point    int
+
+	// optional earnings of limit orders at the point as the pool contract
+	// keeps them in limitOrderData, nil means zero.
+	// EarnY is tokenY earned by selling tokenX and not yet claimed by orders,
+	// AccEarnY is all tokenY ever earned at the point.
+	// when tokenX at the point is sold out, EarnY moves to LegacyEarnY and
+	// LegacyAccEarnY is set to AccEarnY, orders placed before are then filled,
+	// fields of tokenX are the same for selling tokenY
+	EarnX          *big.Int
+	EarnY          *big.Int
+	AccEarnX       *big.Int
+	AccEarnY       *big.Int
+	LegacyEarnX    *big.Int
+	LegacyEarnY    *big.Int
+	LegacyAccEarnX *big.Int
+	LegacyAccEarnY *big.Int
 }
 
 type OrderData struct {
@@ -18,6 +41,9 @@ type OrderData struct {
 	LiquidityIdx  int
 	LimitOrders   []LimitOrderPoint
 	LimitOrderIdx int
+	// decides where the search of next point stops,
+	// built from Liquidities and LimitOrders if nil
+	Bitmap *PointBitmap
 }
 
 func (orderData *OrderData) IsLiquidity(point int) bool {
@@ -38,6 +64,10 @@ func (orderData *OrderData) UnsafeGetDeltaLiquidity() *big.Int {
 	return orderData.Liquidities[orderData.LiquidityIdx].LiqudityDelta
 }
 
+func (orderData *OrderData) UnsafeGetLiquidityPoint() LiquidityPoint {
+	return orderData.Liquidities[orderData.LiquidityIdx]
+}
+
 func (orderData *OrderData) UnsafeGetLimitSellingX() *big.Int {
 	return orderData.LimitOrders[orderData.LimitOrderIdx].SellingX
 }
@@ -45,3 +75,7 @@ func (orderData *OrderData) UnsafeGetLimitSellingX() *big.Int {
 func (orderData *OrderData) UnsafeGetLimitSellingY() *big.Int {
 	return orderData.LimitOrders[orderData.LimitOrderIdx].SellingY
 }
+
+func (orderData *OrderData) UnsafeGetLimitOrder() LimitOrderPoint {
+	return orderData.LimitOrders[orderData.LimitOrderIdx]
+}
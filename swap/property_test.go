@@ -0,0 +1,145 @@
+package swap_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swaptest"
+)
+
+// randomPoolInfo generates a pool of random shape with limit orders
+func randomPoolInfo(t *testing.T, seed int64) swap.PoolInfo {
+	generator, err := swaptest.NewGenerator(seed, swaptest.Config{
+		Shape:             swaptest.Shape(uint64(seed) % 3),
+		MaxLiquidity:      big.NewInt(1e15),
+		LimitOrderDensity: 0.03,
+		MaxLimitOrder:     big.NewInt(1e12),
+		Width:             100,
+	})
+	if err != nil {
+		t.Fatalf("new generator failed: %v", err)
+	}
+	pool, err := generator.PoolInfo()
+	if err != nil {
+		t.Fatalf("generate pool of seed %d failed: %v", seed, err)
+	}
+	return pool
+}
+
+// boundary point of swapType in the whole range of pool
+func mostPt(swapType swap.SwapType, pool swap.PoolInfo) int {
+	if swapType.IsX2Y() {
+		return pool.LeftMostPt
+	}
+	return pool.RightMostPt
+}
+
+// checkedSwap swaps with trace and checks invariants of every step
+func checkedSwap(t *testing.T, swapType swap.SwapType, amount *big.Int, pool swap.PoolInfo) swap.SwapResult {
+	result, trace, err := swap.TraceSwap(swapType, amount, mostPt(swapType, pool), pool)
+	if err != nil {
+		t.Fatalf("%s swap failed: %v", swapType, err)
+	}
+	for idx, step := range trace {
+		if step.LiquidityX.Sign() < 0 || step.LiquidityX.Cmp(step.Liquidity) > 0 {
+			t.Fatalf("%s step %d: liquidityX %s not in [0, liquidity %s]", swapType, idx, step.LiquidityX, step.Liquidity)
+		}
+	}
+	if result.LiquidityX.Sign() < 0 || result.LiquidityX.Cmp(result.Liquidity) > 0 {
+		t.Fatalf("%s result: liquidityX %s not in [0, liquidity %s]", swapType, result.LiquidityX, result.Liquidity)
+	}
+	if !swapType.IsDesire() {
+		payed := result.AmountPayed(swapType)
+		if payed.Cmp(amount) > 0 {
+			t.Fatalf("%s payed %s more than amount %s", swapType, payed, amount)
+		}
+		if new(big.Int).Add(payed, result.AmountRemain).Cmp(amount) != 0 {
+			t.Fatalf("%s payed %s and remain %s not equal to amount %s", swapType, payed, result.AmountRemain, amount)
+		}
+	}
+	return result
+}
+
+func checkSwapProperties(t *testing.T, swapType swap.SwapType, seed int64, amount uint64) {
+	if amount == 0 {
+		return
+	}
+	pool := randomPoolInfo(t, seed)
+	amountIn := new(big.Int).SetUint64(amount)
+	result := checkedSwap(t, swapType, amountIn, pool)
+
+	// output is monotonic in input
+	moreIn := new(big.Int).Add(amountIn, new(big.Int).Rsh(amountIn, 1))
+	moreResult := checkedSwap(t, swapType, moreIn, pool)
+	if moreResult.AmountAcquired(swapType).Cmp(result.AmountAcquired(swapType)) < 0 {
+		t.Fatalf("%s acquire %s for %s less than %s for %s", swapType,
+			moreResult.AmountAcquired(swapType), moreIn, result.AmountAcquired(swapType), amountIn)
+	}
+
+	// desiring the acquired amount costs no more
+	desireType := swap.X2YDesireY
+	if !swapType.IsX2Y() {
+		desireType = swap.Y2XDesireX
+	}
+	acquired := result.AmountAcquired(swapType)
+	if acquired.Sign() > 0 {
+		desireResult := checkedSwap(t, desireType, acquired, pool)
+		if desireResult.AmountPayed(desireType).Cmp(result.AmountPayed(swapType)) > 0 {
+			t.Fatalf("%s desire %s costs %s more than %s", desireType, acquired,
+				desireResult.AmountPayed(desireType), result.AmountPayed(swapType))
+		}
+	}
+
+	// no free round trip, checked without limit orders so that
+	// state of the pool after the swap is known
+	pool.LimitOrders = nil
+	result = checkedSwap(t, swapType, amountIn, pool)
+	acquired = result.AmountAcquired(swapType)
+	if acquired.Sign() == 0 {
+		return
+	}
+	pool.CurrentPoint = result.CurrentPoint
+	pool.Liquidity = result.Liquidity
+	pool.LiquidityX = result.LiquidityX
+	backType := swap.Y2X
+	if !swapType.IsX2Y() {
+		backType = swap.X2Y
+	}
+	backResult := checkedSwap(t, backType, acquired, pool)
+	if backResult.AmountAcquired(backType).Cmp(result.AmountPayed(swapType)) > 0 {
+		t.Fatalf("round trip of %s gets %s more than payed %s", swapType,
+			backResult.AmountAcquired(backType), result.AmountPayed(swapType))
+	}
+}
+
+func FuzzSwapX2Y(f *testing.F) {
+	f.Add(int64(1), uint64(1000000000))
+	f.Add(int64(2), uint64(1))
+	f.Add(int64(3), uint64(1000000000000000000))
+	f.Add(int64(4), uint64(123456789))
+	f.Fuzz(func(t *testing.T, seed int64, amount uint64) {
+		checkSwapProperties(t, swap.X2Y, seed, amount)
+	})
+}
+
+func FuzzSwapY2X(f *testing.F) {
+	f.Add(int64(1), uint64(1000000000))
+	f.Add(int64(2), uint64(1))
+	f.Add(int64(3), uint64(1000000000000000000))
+	f.Add(int64(4), uint64(123456789))
+	f.Fuzz(func(t *testing.T, seed int64, amount uint64) {
+		checkSwapProperties(t, swap.Y2X, seed, amount)
+	})
+}
+
+func TestSwapProperties(t *testing.T) {
+	rnd := rand.New(rand.NewSource(20230601))
+	for i := 0; i < 200; i++ {
+		seed := rnd.Int63()
+		amount := uint64(rnd.Int63n(1e15)) + 1
+		checkSwapProperties(t, swap.X2Y, seed, amount)
+		checkSwapProperties(t, swap.Y2X, seed, amount)
+	}
+}
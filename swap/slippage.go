@@ -0,0 +1,130 @@
+package swap
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+)
+
+// MaxSlippageBps is the denominator of slippage given in basis points
+const MaxSlippageBps = 10000
+
+// SlippageQuote is a swap result together with the bounds
+// expected by the Swap router, minAcquired and maxPayed
+type SlippageQuote struct {
+	SwapType SwapType
+	// input amount for X2Y / Y2X, desired amount for desire swaps
+	Amount *big.Int
+	// lowPt for x2y, highPt for y2x
+	BoundaryPt int
+	// result of the swap on the quoted pool
+	Result SwapResult
+	// the router reverts if acquired amount is less than MinAcquired
+	MinAcquired *big.Int
+	// the router reverts if paid amount is greater than MaxPayed
+	MaxPayed *big.Int
+}
+
+// QuoteWithSlippage quotes a swap and computes minAcquired / maxPayed
+// with a slippage tolerance in basis points (1 bps = 0.01%).
+// for X2Y / Y2X, maxPayed is the input amount and minAcquired is
+// floor(acquired * (10000 - slippageBps) / 10000),
+// for desire swaps, minAcquired is min(acquired, desired) and maxPayed is
+// ceil(paid * (10000 + slippageBps) / 10000).
+// slippageBps must be less than 10000 for X2Y / Y2X, desire swaps accept any
+// non-negative value as paying more than twice the quote is still valid
+func QuoteWithSlippage(
+	swapType SwapType,
+	amount *big.Int,
+	boundaryPt int,
+	pool PoolInfo,
+	slippageBps int,
+) (SlippageQuote, error) {
+	if slippageBps < 0 || (!swapType.IsDesire() && slippageBps >= MaxSlippageBps) {
+		return SlippageQuote{}, fmt.Errorf("invalid slippage %d bps", slippageBps)
+	}
+	quote, err := newSlippageQuote(swapType, amount, boundaryPt, pool)
+	if err != nil {
+		return quote, err
+	}
+	if swapType.IsDesire() {
+		quote.MinAcquired = calc.MinBigInt(quote.Result.AmountAcquired(swapType), amount)
+		quote.MaxPayed = calc.MulDivCeil(
+			quote.Result.AmountPayed(swapType),
+			big.NewInt(MaxSlippageBps+int64(slippageBps)),
+			big.NewInt(MaxSlippageBps),
+		)
+	} else {
+		quote.MinAcquired = calc.MulDivFloor(
+			quote.Result.AmountAcquired(swapType),
+			big.NewInt(MaxSlippageBps-int64(slippageBps)),
+			big.NewInt(MaxSlippageBps),
+		)
+		quote.MaxPayed = new(big.Int).Set(amount)
+	}
+	return quote, nil
+}
+
+// QuoteWithBound quotes a swap with an explicit bound, which is
+// minAcquired for X2Y / Y2X and maxPayed for desire swaps, the other
+// bound is computed like QuoteWithSlippage with zero slippage.
+// a *SlippageError is returned together with the quote if the swap
+// on the given pool already violates the bound
+func QuoteWithBound(
+	swapType SwapType,
+	amount *big.Int,
+	boundaryPt int,
+	pool PoolInfo,
+	bound *big.Int,
+) (SlippageQuote, error) {
+	quote, err := QuoteWithSlippage(swapType, amount, boundaryPt, pool, 0)
+	if err != nil {
+		return quote, err
+	}
+	if swapType.IsDesire() {
+		quote.MaxPayed = new(big.Int).Set(bound)
+	} else {
+		quote.MinAcquired = new(big.Int).Set(bound)
+	}
+	return quote, quote.Check(quote.Result)
+}
+
+// Check returns a *SlippageError if swapResult violates
+// MinAcquired or MaxPayed of the quote
+func (quote SlippageQuote) Check(swapResult SwapResult) error {
+	acquired := swapResult.AmountAcquired(quote.SwapType)
+	payed := swapResult.AmountPayed(quote.SwapType)
+	if acquired.Cmp(quote.MinAcquired) < 0 || payed.Cmp(quote.MaxPayed) > 0 {
+		return &SlippageError{
+			MinAcquired: new(big.Int).Set(quote.MinAcquired),
+			Acquired:    new(big.Int).Set(acquired),
+			MaxPayed:    new(big.Int).Set(quote.MaxPayed),
+			Payed:       new(big.Int).Set(payed),
+		}
+	}
+	return nil
+}
+
+// Verify replays the quoted swap on a newer state of the pool,
+// and reports whether the bounds of the quote would still pass
+func (quote SlippageQuote) Verify(pool PoolInfo) (SwapResult, bool, error) {
+	swapResult, err := Swap(quote.SwapType, quote.Amount, quote.BoundaryPt, pool)
+	if err != nil {
+		return swapResult, false, err
+	}
+	return swapResult, quote.Check(swapResult) == nil, nil
+}
+
+func newSlippageQuote(swapType SwapType, amount *big.Int, boundaryPt int, pool PoolInfo) (SlippageQuote, error) {
+	swapResult, err := Swap(swapType, amount, boundaryPt, pool)
+	if err != nil {
+		return SlippageQuote{}, err
+	}
+	return SlippageQuote{
+		SwapType:   swapType,
+		Amount:     new(big.Int).Set(amount),
+		BoundaryPt: boundaryPt,
+		Result:     swapResult,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package swap
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestQuoteWithSlippageX2Y(t *testing.T) {
+	poolInfo := getPoolInfoX2Y()
+	amount, _ := new(big.Int).SetString("122887485449", 10)
+	quote, err := QuoteWithSlippage(X2Y, amount, -6123, poolInfo, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	minAcquired, _ := new(big.Int).SetString("134155036993", 10)
+	if quote.MinAcquired.Cmp(minAcquired) != 0 {
+		t.Fatalf("minAcquired not equal (%s, %s)", quote.MinAcquired.String(), minAcquired.String())
+	}
+	if quote.MaxPayed.Cmp(amount) != 0 {
+		t.Fatalf("maxPayed not equal (%s, %s)", quote.MaxPayed.String(), amount.String())
+	}
+
+	_, ok, err := quote.Verify(poolInfo)
+	if err != nil || !ok {
+		t.Fatalf("verify on the same pool should pass (%v, %v)", ok, err)
+	}
+
+	// limit order on point 1200 is cancelled before our swap
+	poolInfo.LimitOrders = poolInfo.LimitOrders[:2]
+	swapResult, ok, err := quote.Verify(poolInfo)
+	if err != nil || ok {
+		t.Fatalf("verify on moved pool should fail (%v, %v)", ok, err)
+	}
+	slippageErr, isSlippageErr := quote.Check(swapResult).(*SlippageError)
+	if !isSlippageErr {
+		t.Fatalf("expect SlippageError")
+	}
+	if slippageErr.Acquired.Cmp(swapResult.AmountY) != 0 {
+		t.Fatalf("acquired not equal (%s, %s)", slippageErr.Acquired.String(), swapResult.AmountY.String())
+	}
+}
+
+func TestQuoteWithSlippageY2XDesire(t *testing.T) {
+	poolInfo := getPoolInfoY2X()
+	desire, _ := new(big.Int).SetString("228316826682", 10)
+	quote, err := QuoteWithSlippage(Y2XDesireX, desire, 1100, poolInfo, 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	maxPayed, _ := new(big.Int).SetString("191188107035", 10)
+	if quote.MaxPayed.Cmp(maxPayed) != 0 {
+		t.Fatalf("maxPayed not equal (%s, %s)", quote.MaxPayed.String(), maxPayed.String())
+	}
+	if quote.MinAcquired.Cmp(desire) != 0 {
+		t.Fatalf("minAcquired not equal (%s, %s)", quote.MinAcquired.String(), desire.String())
+	}
+	_, ok, err := quote.Verify(poolInfo)
+	if err != nil || !ok {
+		t.Fatalf("verify on the same pool should pass (%v, %v)", ok, err)
+	}
+
+	// maxPayed may be more than twice of the quote
+	quote, err = QuoteWithSlippage(Y2XDesireX, desire, 1100, poolInfo, 15000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	maxPayed = new(big.Int).Mul(quote.Result.AmountPayed(Y2XDesireX), big.NewInt(25000))
+	maxPayed.Add(maxPayed, big.NewInt(MaxSlippageBps-1)).Div(maxPayed, big.NewInt(MaxSlippageBps))
+	if quote.MaxPayed.Cmp(maxPayed) != 0 {
+		t.Fatalf("maxPayed not equal (%s, %s)", quote.MaxPayed.String(), maxPayed.String())
+	}
+	if _, err = QuoteWithSlippage(Y2XDesireX, desire, 1100, poolInfo, -1); err == nil {
+		t.Fatalf("expect error for negative slippage")
+	}
+}
+
+func TestQuoteWithBound(t *testing.T) {
+	poolInfo := getPoolInfoX2Y()
+	amount, _ := new(big.Int).SetString("122887485449", 10)
+	minAcquired, _ := new(big.Int).SetString("134829182909", 10)
+	_, err := QuoteWithBound(X2Y, amount, -6123, poolInfo, minAcquired)
+	if _, ok := err.(*SlippageError); !ok {
+		t.Fatalf("expect SlippageError, got %v", err)
+	}
+	minAcquired.SetString("134829182908", 10)
+	_, err = QuoteWithBound(X2Y, amount, -6123, poolInfo, minAcquired)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = QuoteWithSlippage(X2Y, amount, -6123, poolInfo, MaxSlippageBps); err == nil {
+		t.Fatalf("expect error for invalid slippage")
+	}
+}
@@ -0,0 +1,55 @@
+package swap
+
+import "math/big"
+
+// StopReason describes why a swap stopped
+type StopReason int
+
+const (
+	// input amount is spent (or desired amount is acquired)
+	StopAmountRunOut StopReason = iota
+	// remaining amount is too small to be swapped at current price
+	StopAmountDust
+	// boundary point (lowPt or highPt) of the swap is reached
+	StopBoundaryPt
+	// LeftMostPt or RightMostPt of the pool is reached
+	StopMostPt
+	// no liquidity or limit order remains in the direction of the swap
+	StopLiquidityRunOut
+)
+
+func (reason StopReason) String() string {
+	switch reason {
+	case StopAmountRunOut:
+		return "AmountRunOut"
+	case StopAmountDust:
+		return "AmountDust"
+	case StopBoundaryPt:
+		return "BoundaryPt"
+	case StopMostPt:
+		return "MostPt"
+	case StopLiquidityRunOut:
+		return "LiquidityRunOut"
+	}
+	return "Unknown"
+}
+
+// getStopReason classifies the end of a swap,
+// remain is the unspent input (or unacquired desire) amount,
+// reachBoundary means the swap stopped at the (clamped) boundary point,
+// boundaryIsMost means that boundary point is LeftMostPt or RightMostPt of pool
+func getStopReason(remain *big.Int, reachBoundary, boundaryIsMost, noMore bool) StopReason {
+	if remain.Cmp(zeroBI) <= 0 {
+		return StopAmountRunOut
+	}
+	if !reachBoundary {
+		return StopAmountDust
+	}
+	if noMore {
+		return StopLiquidityRunOut
+	}
+	if boundaryIsMost {
+		return StopMostPt
+	}
+	return StopBoundaryPt
+}
@@ -0,0 +1,68 @@
+package swap
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+)
+
+func TestStrictEVMSameResult(t *testing.T) {
+	poolInfo := getPoolInfoX2Y()
+	var amount big.Int
+	amount.SetString("100000000000000000000000", 10)
+	expect, _ := SwapX2Y(&amount, -6123, poolInfo)
+
+	poolInfo.StrictEVM = true
+	result, err := SwapX2Y(&amount, -6123, poolInfo)
+	if err != nil {
+		t.Fatalf("strict swap failed: %v", err)
+	}
+	if result.AmountX.Cmp(expect.AmountX) != 0 || result.AmountY.Cmp(expect.AmountY) != 0 {
+		t.Fatalf("amount not equal (%s, %s), (%s, %s)",
+			result.AmountX.String(), result.AmountY.String(), expect.AmountX.String(), expect.AmountY.String())
+	}
+}
+
+func TestStrictEVMAmountOverflow(t *testing.T) {
+	poolInfo := getPoolInfoX2Y()
+	amount := new(big.Int).Lsh(big.NewInt(1), 128)
+	if _, err := SwapX2Y(amount, -6123, poolInfo); err != nil {
+		t.Fatalf("swap should not check bounds without strict mode: %v", err)
+	}
+
+	strictPool := poolInfo
+	strictPool.StrictEVM = true
+	_, err := SwapX2Y(amount, -6123, strictPool)
+	var revert *calc.Revert
+	if !errors.As(err, &revert) || revert.Code != calc.PanicArithmetic {
+		t.Fatalf("expect arithmetic panic, got %v", err)
+	}
+	// strict mode of one pool does not leak to others
+	if _, err := SwapX2Y(amount, -6123, poolInfo); err != nil {
+		t.Fatalf("swap should not check bounds without strict mode: %v", err)
+	}
+}
+
+func TestStrictEVMLiquidityUnderflow(t *testing.T) {
+	poolInfo := PoolInfo{
+		CurrentPoint: 0,
+		PointDelta:   40,
+		LeftMostPt:   -800000,
+		RightMostPt:  800000,
+		Fee:          2000,
+		Liquidity:    big.NewInt(100000),
+		LiquidityX:   big.NewInt(0),
+		Liquidities: []LiquidityPoint{
+			{LiqudityDelta: big.NewInt(200000), Point: -400},
+		},
+		StrictEVM: true,
+	}
+
+	_, err := SwapX2Y(big.NewInt(1000000000), -800, poolInfo)
+	var revert *calc.Revert
+	if !errors.As(err, &revert) || revert.Reason != "LS" {
+		t.Fatalf("expect revert LS, got %v", err)
+	}
+}
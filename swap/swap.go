@@ -0,0 +1,93 @@
+package swap
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// SwapType is one of the four swap interfaces of the pool
+type SwapType int
+
+const (
+	// pay tokenX with given input amount
+	X2Y SwapType = iota
+	// pay tokenY with given input amount
+	Y2X
+	// pay tokenX with given desired amount of tokenY
+	X2YDesireY
+	// pay tokenY with given desired amount of tokenX
+	Y2XDesireX
+)
+
+func (swapType SwapType) String() string {
+	switch swapType {
+	case X2Y:
+		return "X2Y"
+	case Y2X:
+		return "Y2X"
+	case X2YDesireY:
+		return "X2YDesireY"
+	case Y2XDesireX:
+		return "Y2XDesireX"
+	}
+	return "Unknown"
+}
+
+// ParseSwapType parses x2y, y2x, x2y-desire or y2x-desire,
+// names returned by SwapType.String are also accepted
+func ParseSwapType(name string) (SwapType, error) {
+	switch strings.ToLower(name) {
+	case "x2y":
+		return X2Y, nil
+	case "y2x":
+		return Y2X, nil
+	case "x2y-desire", "x2ydesirey":
+		return X2YDesireY, nil
+	case "y2x-desire", "y2xdesirex":
+		return Y2XDesireX, nil
+	}
+	return 0, fmt.Errorf("unknown swap type %s", name)
+}
+
+// IsX2Y reports whether tokenX is paid in this swap
+func (swapType SwapType) IsX2Y() bool {
+	return swapType == X2Y || swapType == X2YDesireY
+}
+
+// IsDesire reports whether amount of this swap is the desired output
+func (swapType SwapType) IsDesire() bool {
+	return swapType == X2YDesireY || swapType == Y2XDesireX
+}
+
+// Swap calls SwapX2Y, SwapY2X, SwapX2YDesireY or SwapY2XDesireX
+// according to swapType, boundaryPt is lowPt for x2y and highPt for y2x
+func Swap(swapType SwapType, amount *big.Int, boundaryPt int, pool PoolInfo) (SwapResult, error) {
+	switch swapType {
+	case X2Y:
+		return SwapX2Y(amount, boundaryPt, pool)
+	case Y2X:
+		return SwapY2X(amount, boundaryPt, pool)
+	case X2YDesireY:
+		return SwapX2YDesireY(amount, boundaryPt, pool)
+	case Y2XDesireX:
+		return SwapY2XDesireX(amount, boundaryPt, pool)
+	}
+	return SwapResult{}, fmt.Errorf("unknown swap type %d", swapType)
+}
+
+// AmountPayed returns the amount of token paid in a swap of swapType
+func (swapResult SwapResult) AmountPayed(swapType SwapType) *big.Int {
+	if swapType.IsX2Y() {
+		return swapResult.AmountX
+	}
+	return swapResult.AmountY
+}
+
+// AmountAcquired returns the amount of token acquired in a swap of swapType
+func (swapResult SwapResult) AmountAcquired(swapType SwapType) *big.Int {
+	if swapType.IsX2Y() {
+		return swapResult.AmountY
+	}
+	return swapResult.AmountX
+}
@@ -10,9 +10,21 @@ import (
 )
 
 func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
+	return swapX2Y(amount, lowPt, pool, nil)
+}
+
+func swapX2Y(amount *big.Int, lowPt int, pool PoolInfo, trace *Trace) (result SwapResult, err error) {
+	defer calc.RecoverRevert(&err)
+	evm := pool.evm()
 	if amount.Cmp(big.NewInt(0)) <= 0 {
 		return SwapResult{}, fmt.Errorf("AP")
 	}
+	if err := checkBitmap(pool); err != nil {
+		return SwapResult{}, err
+	}
+	amount = new(big.Int).Set(amount)
+	// uint128 amount of the contract
+	evm.RequireUint128(amount)
 
 	lowPt = calc.Max(lowPt, pool.LeftMostPt)
 	amountX := big.NewInt(0)
@@ -22,6 +34,7 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 
 	liquidityX := new(big.Int).Set(pool.LiquidityX)
 	liquidity := new(big.Int).Set(pool.Liquidity)
+	feeScales := newFeeScales(pool)
 
 	finished := false
 	sqrtRate_96, _ := calc.GetSqrtPrice(1)
@@ -29,11 +42,15 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 	currentPoint := pool.CurrentPoint
 	fee := pool.Fee
 
+	var events SwapEvents
+	var limitOrders []LimitOrderPoint
+
 	orderData := InitX2Y(
 		pool.Liquidities,
 		pool.LimitOrders,
 		pool.CurrentPoint,
 	)
+	orderData.Bitmap = pool.Bitmap
 
 	for lowPt <= currentPoint && !finished {
 		if orderData.IsLimitOrder(currentPoint) {
@@ -43,7 +60,7 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 			if amountNoFee.Cmp(big.NewInt(0)) > 0 {
 
 				currY := orderData.UnsafeGetLimitSellingY()
-				costX, acquireY := swapmath.X2YAtPrice(amountNoFee, sqrtPrice_96, currY)
+				costX, acquireY := swapmath.X2YAtPriceEVM(evm, amountNoFee, sqrtPrice_96, currY)
 
 				if acquireY.Cmp(currY) < 0 || costX.Cmp(amountNoFee) >= 0 {
 					finished = true
@@ -69,7 +86,22 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 				amountX.Add(amountX, feeAmount)
 				amountY.Add(amountY, acquireY)
 
+				limitOrders = append(limitOrders, orderData.UnsafeGetLimitOrder().sellY(evm, costX, acquireY))
 				orderData.ConsumeLimitOrder(false)
+				events.LimitOrders++
+				feeScaleDelta, feeCharged := feeScales.addX(feeAmount, liquidity)
+				trace.add(TraceStep{
+					Kind:              StepLimitOrder,
+					FromPoint:         currentPoint,
+					ToPoint:           currentPoint,
+					AmountX:           new(big.Int).Add(costX, feeAmount),
+					AmountY:           acquireY,
+					FeeAmount:         feeAmount,
+					FeeCharged:        feeCharged,
+					FeeScaleDelta_128: feeScaleDelta,
+					Liquidity:         liquidity,
+					LiquidityX:        liquidityX,
+				})
 			} else {
 				finished = true
 			}
@@ -93,7 +125,8 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 						CurrentPoint: currentPoint,
 						SqrtPrice_96: sqrtPrice_96,
 					}
-					retState := swapmath.X2YRange(st, currentPoint, sqrtRate_96, new(big.Int).Set(amountNoFee))
+					retState := swapmath.X2YRangeEVM(evm, st, currentPoint, sqrtRate_96, new(big.Int).Set(amountNoFee))
+					events.Ranges++
 					finished = retState.Finished
 
 					feeAmount := new(big.Int)
@@ -117,13 +150,36 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 					currentPoint = retState.FinalPt
 					sqrtPrice_96 = retState.SqrtFinalPrice_96
 					liquidityX = retState.LiquidityX
+					feeScaleDelta, feeCharged := feeScales.addX(feeAmount, liquidity)
+					trace.add(TraceStep{
+						Kind:              StepRange,
+						FromPoint:         st.CurrentPoint,
+						ToPoint:           currentPoint,
+						AmountX:           new(big.Int).Add(retState.CostX, feeAmount),
+						AmountY:           retState.AcquireY,
+						FeeAmount:         feeAmount,
+						FeeCharged:        feeCharged,
+						FeeScaleDelta_128: feeScaleDelta,
+						Liquidity:         liquidity,
+						LiquidityX:        liquidityX,
+					})
 				}
 				if !finished {
+					feeScales.pass(orderData.UnsafeGetLiquidityPoint())
 					delta := orderData.UnsafeGetDeltaLiquidity()
-					liquidity.Sub(liquidity, delta)
+					liquidity.Set(evm.AddDelta(liquidity, new(big.Int).Neg(delta)))
+					events.Endpoints++
 					currentPoint -= 1
 					sqrtPrice_96, _ = calc.GetSqrtPrice(currentPoint)
 					liquidityX.SetInt64(0)
+					trace.add(TraceStep{
+						Kind:           StepEndpoint,
+						FromPoint:      currentPoint + 1,
+						ToPoint:        currentPoint,
+						LiquidityDelta: delta,
+						Liquidity:      liquidity,
+						LiquidityX:     liquidityX,
+					})
 				}
 			} else {
 				finished = true
@@ -135,6 +191,7 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 		}
 
 		nextPt := orderData.MoveX2Y(searchStart, pointDelta)
+		events.BitmapWords++
 		if nextPt < lowPt {
 			nextPt = lowPt
 		}
@@ -152,7 +209,8 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 					CurrentPoint: currentPoint,
 					SqrtPrice_96: sqrtPrice_96,
 				}
-				retState := swapmath.X2YRange(st, nextPt, sqrtRate_96, new(big.Int).Set(amountNoFee))
+				retState := swapmath.X2YRangeEVM(evm, st, nextPt, sqrtRate_96, new(big.Int).Set(amountNoFee))
+				events.Ranges++
 				finished = retState.Finished
 				feeAmount := new(big.Int)
 				if retState.CostX.Cmp(amountNoFee) >= 0 {
@@ -175,6 +233,19 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 				currentPoint = retState.FinalPt
 				sqrtPrice_96 = retState.SqrtFinalPrice_96
 				liquidityX = retState.LiquidityX
+				feeScaleDelta, feeCharged := feeScales.addX(feeAmount, liquidity)
+				trace.add(TraceStep{
+					Kind:              StepRange,
+					FromPoint:         st.CurrentPoint,
+					ToPoint:           currentPoint,
+					AmountX:           new(big.Int).Add(retState.CostX, feeAmount),
+					AmountY:           retState.AcquireY,
+					FeeAmount:         feeAmount,
+					FeeCharged:        feeCharged,
+					FeeScaleDelta_128: feeScaleDelta,
+					Liquidity:         liquidity,
+					LiquidityX:        liquidityX,
+				})
 			} else {
 				finished = true
 			}
@@ -186,11 +257,25 @@ func SwapX2Y(amount *big.Int, lowPt int, pool PoolInfo) (SwapResult, error) {
 	}
 
 	swapResult := SwapResult{
-		CurrentPoint: currentPoint,
-		Liquidity:    liquidity,
-		LiquidityX:   liquidityX,
-		AmountX:      amountX,
-		AmountY:      amountY,
+		CurrentPoint:  currentPoint,
+		Liquidity:     liquidity,
+		LiquidityX:    liquidityX,
+		AmountX:       amountX,
+		AmountY:       amountY,
+		AmountRemain:  amount,
+		Events:        events,
+		LimitOrders:   limitOrders,
+		FeeScaleX_128: feeScales.x,
+		FeeScaleY_128: feeScales.y,
+		FeeXCharged:   feeScales.chargedX,
+		FeeYCharged:   feeScales.chargedY,
+		Endpoints:     feeScales.endpoints,
+		StopReason: getStopReason(
+			amount,
+			currentPoint <= lowPt,
+			lowPt == pool.LeftMostPt,
+			orderData.noMoreX2Y(currentPoint),
+		),
 	}
 	return swapResult, nil
 }
@@ -14,10 +14,26 @@ func SwapX2YDesireY(
 	lowPt int,
 	pool PoolInfo,
 ) (SwapResult, error) {
+	return swapX2YDesireY(desireY, lowPt, pool, nil)
+}
+
+func swapX2YDesireY(
+	desireY *big.Int,
+	lowPt int,
+	pool PoolInfo,
+	trace *Trace,
+) (result SwapResult, err error) {
+	defer calc.RecoverRevert(&err)
+	evm := pool.evm()
 	if desireY.Cmp(big.NewInt(0)) <= 0 {
 		return SwapResult{}, fmt.Errorf("AP")
 	}
+	if err := checkBitmap(pool); err != nil {
+		return SwapResult{}, err
+	}
 	desireY = new(big.Int).Set(desireY)
+	// uint128 amount of the contract
+	evm.RequireUint128(desireY)
 
 	lowPt = calc.Max(lowPt, pool.LeftMostPt)
 	amountX := big.NewInt(0)
@@ -27,6 +43,7 @@ func SwapX2YDesireY(
 
 	liquidityX := new(big.Int).Set(pool.LiquidityX)
 	liquidity := new(big.Int).Set(pool.Liquidity)
+	feeScales := newFeeScales(pool)
 
 	finished := false
 	sqrtRate_96, _ := calc.GetSqrtPrice(1)
@@ -34,24 +51,28 @@ func SwapX2YDesireY(
 	currentPoint := pool.CurrentPoint
 	fee := int64(pool.Fee)
 
+	var events SwapEvents
+	var limitOrders []LimitOrderPoint
+
 	orderData := InitX2Y(
 		pool.Liquidities,
 		pool.LimitOrders,
 		pool.CurrentPoint,
 	)
+	orderData.Bitmap = pool.Bitmap
 
 	for lowPt <= currentPoint && !finished {
 		// clear limit order first
 		if orderData.IsLimitOrder(currentPoint) {
 			currY := orderData.UnsafeGetLimitSellingY()
 
-			costX, acquireY := swapmathdesire.X2YAtPrice(desireY, sqrtPrice_96, currY)
+			costX, acquireY := swapmathdesire.X2YAtPriceEVM(evm, desireY, sqrtPrice_96, currY)
 
 			if acquireY.Cmp(desireY) >= 0 {
 				finished = true
 			}
 
-			feeAmount := calc.MulDivCeil(
+			feeAmount := evm.MulDivCeil(
 				costX,
 				new(big.Int).SetInt64(fee),
 				new(big.Int).SetInt64(1e6-fee),
@@ -60,7 +81,22 @@ func SwapX2YDesireY(
 			amountX.Add(amountX, costX)
 			amountX.Add(amountX, feeAmount)
 			amountY.Add(amountY, acquireY)
+			limitOrders = append(limitOrders, orderData.UnsafeGetLimitOrder().sellY(evm, costX, acquireY))
 			orderData.ConsumeLimitOrder(false)
+			events.LimitOrders++
+			feeScaleDelta, feeCharged := feeScales.addX(feeAmount, liquidity)
+			trace.add(TraceStep{
+				Kind:              StepLimitOrder,
+				FromPoint:         currentPoint,
+				ToPoint:           currentPoint,
+				AmountX:           new(big.Int).Add(costX, feeAmount),
+				AmountY:           acquireY,
+				FeeAmount:         feeAmount,
+				FeeCharged:        feeCharged,
+				FeeScaleDelta_128: feeScaleDelta,
+				Liquidity:         liquidity,
+				LiquidityX:        liquidityX,
+			})
 		}
 		if finished {
 			break
@@ -75,10 +111,11 @@ func SwapX2YDesireY(
 					CurrentPoint: currentPoint,
 					SqrtPrice_96: sqrtPrice_96,
 				}
-				retState := swapmathdesire.X2YRange(st, currentPoint, sqrtRate_96, desireY)
+				retState := swapmathdesire.X2YRangeEVM(evm, st, currentPoint, sqrtRate_96, desireY)
+				events.Ranges++
 				finished = retState.Finished
 
-				feeAmount := calc.MulDivCeil(
+				feeAmount := evm.MulDivCeil(
 					retState.CostX,
 					new(big.Int).SetInt64(fee),
 					new(big.Int).SetInt64(1e6-fee),
@@ -92,13 +129,36 @@ func SwapX2YDesireY(
 				currentPoint = retState.FinalPt
 				sqrtPrice_96 = retState.SqrtFinalPrice_96
 				liquidityX = retState.LiquidityX
+				feeScaleDelta, feeCharged := feeScales.addX(feeAmount, liquidity)
+				trace.add(TraceStep{
+					Kind:              StepRange,
+					FromPoint:         st.CurrentPoint,
+					ToPoint:           currentPoint,
+					AmountX:           new(big.Int).Add(retState.CostX, feeAmount),
+					AmountY:           retState.AcquireY,
+					FeeAmount:         feeAmount,
+					FeeCharged:        feeCharged,
+					FeeScaleDelta_128: feeScaleDelta,
+					Liquidity:         liquidity,
+					LiquidityX:        liquidityX,
+				})
 			}
 			if !finished {
+				feeScales.pass(orderData.UnsafeGetLiquidityPoint())
 				delta := orderData.UnsafeGetDeltaLiquidity()
-				liquidity.Sub(liquidity, delta)
+				liquidity.Set(evm.AddDelta(liquidity, new(big.Int).Neg(delta)))
+				events.Endpoints++
 				currentPoint -= 1
 				sqrtPrice_96, _ = calc.GetSqrtPrice(currentPoint)
 				liquidityX.SetInt64(0)
+				trace.add(TraceStep{
+					Kind:           StepEndpoint,
+					FromPoint:      currentPoint + 1,
+					ToPoint:        currentPoint,
+					LiquidityDelta: delta,
+					Liquidity:      liquidity,
+					LiquidityX:     liquidityX,
+				})
 			}
 		}
 		if finished || currentPoint < lowPt {
@@ -106,6 +166,7 @@ func SwapX2YDesireY(
 		}
 
 		nextPt := orderData.MoveX2Y(searchStart, pointDelta)
+		events.BitmapWords++
 		if nextPt < lowPt {
 			nextPt = lowPt
 		}
@@ -123,12 +184,13 @@ func SwapX2YDesireY(
 				CurrentPoint: currentPoint,
 				SqrtPrice_96: sqrtPrice_96,
 			}
-			retState := swapmathdesire.X2YRange(
-				st, nextPt, sqrtRate_96, desireY,
+			retState := swapmathdesire.X2YRangeEVM(
+				evm, st, nextPt, sqrtRate_96, desireY,
 			)
+			events.Ranges++
 			finished = retState.Finished
 
-			feeAmount := calc.MulDivCeil(
+			feeAmount := evm.MulDivCeil(
 				retState.CostX,
 				new(big.Int).SetInt64(fee),
 				new(big.Int).SetInt64(1e6-fee),
@@ -142,6 +204,19 @@ func SwapX2YDesireY(
 			currentPoint = retState.FinalPt
 			sqrtPrice_96 = retState.SqrtFinalPrice_96
 			liquidityX = retState.LiquidityX
+			feeScaleDelta, feeCharged := feeScales.addX(feeAmount, liquidity)
+			trace.add(TraceStep{
+				Kind:              StepRange,
+				FromPoint:         st.CurrentPoint,
+				ToPoint:           currentPoint,
+				AmountX:           new(big.Int).Add(retState.CostX, feeAmount),
+				AmountY:           retState.AcquireY,
+				FeeAmount:         feeAmount,
+				FeeCharged:        feeCharged,
+				FeeScaleDelta_128: feeScaleDelta,
+				Liquidity:         liquidity,
+				LiquidityX:        liquidityX,
+			})
 		}
 
 		if currentPoint <= lowPt {
@@ -150,11 +225,47 @@ func SwapX2YDesireY(
 	}
 
 	swapResult := SwapResult{
-		CurrentPoint: currentPoint,
-		Liquidity:    liquidity,
-		LiquidityX:   liquidityX,
-		AmountX:      amountX,
-		AmountY:      amountY,
+		CurrentPoint:  currentPoint,
+		Liquidity:     liquidity,
+		LiquidityX:    liquidityX,
+		AmountX:       amountX,
+		AmountY:       amountY,
+		AmountRemain:  calc.MaxBigInt(desireY, big.NewInt(0)),
+		Events:        events,
+		LimitOrders:   limitOrders,
+		FeeScaleX_128: feeScales.x,
+		FeeScaleY_128: feeScales.y,
+		FeeXCharged:   feeScales.chargedX,
+		FeeYCharged:   feeScales.chargedY,
+		Endpoints:     feeScales.endpoints,
+		StopReason: getStopReason(
+			desireY,
+			currentPoint <= lowPt,
+			lowPt == pool.LeftMostPt,
+			orderData.noMoreX2Y(currentPoint),
+		),
+	}
+	return swapResult, nil
+}
+
+// SwapX2YDesireYStrict is the same as SwapX2YDesireY, but returns
+// a *DesireNotSatisfiedError together with the result if the acquired
+// tokenY is less than desireY
+func SwapX2YDesireYStrict(
+	desireY *big.Int,
+	lowPt int,
+	pool PoolInfo,
+) (SwapResult, error) {
+	swapResult, err := SwapX2YDesireY(desireY, lowPt, pool)
+	if err != nil {
+		return swapResult, err
+	}
+	if swapResult.AmountRemain.Cmp(big.NewInt(0)) > 0 {
+		return swapResult, &DesireNotSatisfiedError{
+			Desire:  new(big.Int).Set(desireY),
+			Acquire: new(big.Int).Set(swapResult.AmountY),
+			Remain:  new(big.Int).Set(swapResult.AmountRemain),
+		}
 	}
 	return swapResult, nil
 }
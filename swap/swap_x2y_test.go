@@ -206,3 +206,65 @@ func TestSwapX2YDesire5(t *testing.T) {
 		t.Fatalf("amount y not equal (%s, %s)", swapAmount.AmountY.String(), acquireY.String())
 	}
 }
+
+func TestSwapX2YDesireStrict1(t *testing.T) {
+	poolInfo := getPoolInfoX2Y()
+	var amount big.Int
+	amount.SetString("100000000000000000000000", 10)
+	swapAmount, err := SwapX2YDesireYStrict(&amount, -6123, poolInfo)
+	notSatisfied, ok := err.(*DesireNotSatisfiedError)
+	if !ok {
+		t.Fatalf("expect DesireNotSatisfiedError, got %v", err)
+	}
+	remain, _ := new(big.Int).SetString("99999999999628284951765", 10)
+	if notSatisfied.Remain.Cmp(remain) != 0 {
+		t.Fatalf("remain not equal (%s, %s)", notSatisfied.Remain.String(), remain.String())
+	}
+	if swapAmount.AmountRemain.Cmp(remain) != 0 {
+		t.Fatalf("amount remain not equal (%s, %s)", swapAmount.AmountRemain.String(), remain.String())
+	}
+}
+
+func TestSwapX2YDesireStrict2(t *testing.T) {
+	poolInfo := getPoolInfoX2Y()
+	var amount big.Int
+	amount.SetString("341243701400", 10)
+	swapAmount, err := SwapX2YDesireYStrict(&amount, -6123, poolInfo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if swapAmount.AmountRemain.Sign() != 0 {
+		t.Fatalf("amount remain not zero (%s)", swapAmount.AmountRemain.String())
+	}
+}
+
+func TestSwapX2YStopReason(t *testing.T) {
+	cases := []struct {
+		amount     string
+		lowPt      int
+		leftMostPt int
+		reason     StopReason
+		remain     string
+	}{
+		{"410079196782", -6123, -800000, StopAmountRunOut, "0"},
+		{"399624951498", -6123, -800000, StopAmountDust, "1"},
+		{"100000000000000000000000", -6123, -800000, StopBoundaryPt, "99999999999589920803218"},
+		{"100000000000000000000000", -800000, -5000, StopMostPt, "99999999999590663903663"},
+		{"100000000000000000000000", -800000, -800000, StopLiquidityRunOut, "99999999999588275164290"},
+	}
+	for idx, c := range cases {
+		poolInfo := getPoolInfoX2Y()
+		poolInfo.LeftMostPt = c.leftMostPt
+		amount, _ := new(big.Int).SetString(c.amount, 10)
+		swapResult, _ := SwapX2Y(amount, c.lowPt, poolInfo)
+		if amount.String() != c.amount {
+			t.Fatalf("case %d: input amount modified (%s)", idx, amount.String())
+		}
+		if swapResult.StopReason != c.reason {
+			t.Fatalf("case %d: stop reason not equal (%s, %s)", idx, swapResult.StopReason, c.reason)
+		}
+		if swapResult.AmountRemain.String() != c.remain {
+			t.Fatalf("case %d: amount remain not equal (%s, %s)", idx, swapResult.AmountRemain.String(), c.remain)
+		}
+	}
+}
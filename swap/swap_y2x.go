@@ -10,9 +10,21 @@ import (
 )
 
 func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
+	return swapY2X(amount, highPt, pool, nil)
+}
+
+func swapY2X(amount *big.Int, highPt int, pool PoolInfo, trace *Trace) (result SwapResult, err error) {
+	defer calc.RecoverRevert(&err)
+	evm := pool.evm()
 	if amount.Cmp(big.NewInt(0)) <= 0 {
 		return SwapResult{}, fmt.Errorf("AP")
 	}
+	if err := checkBitmap(pool); err != nil {
+		return SwapResult{}, err
+	}
+	amount = new(big.Int).Set(amount)
+	// uint128 amount of the contract
+	evm.RequireUint128(amount)
 
 	highPt = calc.Min(highPt, pool.RightMostPt)
 
@@ -23,6 +35,7 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 
 	liquidityX := new(big.Int).Set(pool.LiquidityX)
 	liquidity := new(big.Int).Set(pool.Liquidity)
+	feeScales := newFeeScales(pool)
 
 	finished := false
 	sqrtRate_96, _ := calc.GetSqrtPrice(1)
@@ -30,11 +43,15 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 	currentPoint := pool.CurrentPoint
 	fee := pool.Fee
 
+	var events SwapEvents
+	var limitOrders []LimitOrderPoint
+
 	orderData := InitY2X(
 		pool.Liquidities,
 		pool.LimitOrders,
 		pool.CurrentPoint,
 	)
+	orderData.Bitmap = pool.Bitmap
 
 	for currentPoint < highPt && !finished {
 		if orderData.IsLimitOrder(currentPoint) {
@@ -44,7 +61,7 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 			if amountNoFee.Cmp(big.NewInt(0)) > 0 {
 				// clear limit order first
 				currX := orderData.UnsafeGetLimitSellingX()
-				costY, acquireX := swapmath.Y2XAtPrice(amountNoFee, sqrtPrice_96, currX)
+				costY, acquireX := swapmath.Y2XAtPriceEVM(evm, amountNoFee, sqrtPrice_96, currX)
 				if acquireX.Cmp(currX) < 0 || costY.Cmp(amountNoFee) >= 0 {
 					finished = true
 				}
@@ -63,7 +80,22 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 				amount.Sub(amount, new(big.Int).Add(costY, feeAmount))
 				amountY.Add(amountY, new(big.Int).Add(costY, feeAmount))
 				amountX.Add(amountX, acquireX)
+				limitOrders = append(limitOrders, orderData.UnsafeGetLimitOrder().sellX(evm, costY, acquireX))
 				orderData.ConsumeLimitOrder(true)
+				events.LimitOrders++
+				feeScaleDelta, feeCharged := feeScales.addY(feeAmount, liquidity)
+				trace.add(TraceStep{
+					Kind:              StepLimitOrder,
+					FromPoint:         currentPoint,
+					ToPoint:           currentPoint,
+					AmountX:           acquireX,
+					AmountY:           new(big.Int).Add(costY, feeAmount),
+					FeeAmount:         feeAmount,
+					FeeCharged:        feeCharged,
+					FeeScaleDelta_128: feeScaleDelta,
+					Liquidity:         liquidity,
+					LiquidityX:        liquidityX,
+				})
 			} else {
 				finished = true
 			}
@@ -74,6 +106,7 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 		}
 
 		nextPoint := orderData.MoveY2X(currentPoint, pointDelta)
+		events.BitmapWords++
 		if nextPoint > highPt {
 			nextPoint = highPt
 		}
@@ -84,8 +117,18 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 			currentPoint = nextPoint
 			sqrtPrice_96, _ = calc.GetSqrtPrice(currentPoint)
 			if orderData.IsLiquidity(currentPoint) {
+				feeScales.pass(orderData.UnsafeGetLiquidityPoint())
 				delta := orderData.UnsafeGetDeltaLiquidity()
-				liquidity.Add(liquidity, delta)
+				liquidity.Set(evm.AddDelta(liquidity, delta))
+				events.Endpoints++
+				trace.add(TraceStep{
+					Kind:           StepEndpoint,
+					FromPoint:      currentPoint,
+					ToPoint:        currentPoint,
+					LiquidityDelta: delta,
+					Liquidity:      liquidity,
+					LiquidityX:     liquidity,
+				})
 				liquidityX = liquidity
 			}
 		} else {
@@ -99,7 +142,8 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 					CurrentPoint: currentPoint,
 					SqrtPrice_96: sqrtPrice_96,
 				}
-				retState := swapmath.Y2XRange(st, nextPoint, sqrtRate_96, new(big.Int).Set(amountNoFee))
+				retState := swapmath.Y2XRangeEVM(evm, st, nextPoint, sqrtRate_96, new(big.Int).Set(amountNoFee))
+				events.Ranges++
 
 				finished = retState.Finished
 				var feeAmount *big.Int
@@ -122,14 +166,37 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 				currentPoint = retState.FinalPt
 				sqrtPrice_96 = retState.SqrtFinalPrice_96
 				liquidityX = retState.LiquidityX
+				feeScaleDelta, feeCharged := feeScales.addY(feeAmount, liquidity)
+				trace.add(TraceStep{
+					Kind:              StepRange,
+					FromPoint:         st.CurrentPoint,
+					ToPoint:           currentPoint,
+					AmountX:           retState.AcquireX,
+					AmountY:           new(big.Int).Add(retState.CostY, feeAmount),
+					FeeAmount:         feeAmount,
+					FeeCharged:        feeCharged,
+					FeeScaleDelta_128: feeScaleDelta,
+					Liquidity:         liquidity,
+					LiquidityX:        liquidityX,
+				})
 			} else {
 				finished = true
 			}
 
 			if currentPoint == nextPoint {
 				if orderData.IsLiquidity(nextPoint) {
+					feeScales.pass(orderData.UnsafeGetLiquidityPoint())
 					delta := orderData.UnsafeGetDeltaLiquidity()
-					liquidity.Add(liquidity, delta)
+					liquidity.Set(evm.AddDelta(liquidity, delta))
+					events.Endpoints++
+					trace.add(TraceStep{
+						Kind:           StepEndpoint,
+						FromPoint:      currentPoint,
+						ToPoint:        currentPoint,
+						LiquidityDelta: delta,
+						Liquidity:      liquidity,
+						LiquidityX:     liquidity,
+					})
 				}
 				liquidityX = liquidity
 			}
@@ -137,11 +204,25 @@ func SwapY2X(amount *big.Int, highPt int, pool PoolInfo) (SwapResult, error) {
 	}
 
 	swapResult := SwapResult{
-		CurrentPoint: currentPoint,
-		Liquidity:    liquidity,
-		LiquidityX:   liquidityX,
-		AmountX:      amountX,
-		AmountY:      amountY,
+		CurrentPoint:  currentPoint,
+		Liquidity:     liquidity,
+		LiquidityX:    liquidityX,
+		AmountX:       amountX,
+		AmountY:       amountY,
+		AmountRemain:  amount,
+		Events:        events,
+		LimitOrders:   limitOrders,
+		FeeScaleX_128: feeScales.x,
+		FeeScaleY_128: feeScales.y,
+		FeeXCharged:   feeScales.chargedX,
+		FeeYCharged:   feeScales.chargedY,
+		Endpoints:     feeScales.endpoints,
+		StopReason: getStopReason(
+			amount,
+			currentPoint >= highPt,
+			highPt == pool.RightMostPt,
+			orderData.noMoreY2X(currentPoint),
+		),
 	}
 	return swapResult, nil
 }
@@ -14,10 +14,26 @@ func SwapY2XDesireX(
 	highPt int,
 	pool PoolInfo,
 ) (SwapResult, error) {
+	return swapY2XDesireX(desireX, highPt, pool, nil)
+}
+
+func swapY2XDesireX(
+	desireX *big.Int,
+	highPt int,
+	pool PoolInfo,
+	trace *Trace,
+) (result SwapResult, err error) {
+	defer calc.RecoverRevert(&err)
+	evm := pool.evm()
 	if desireX.Cmp(big.NewInt(0)) <= 0 {
 		return SwapResult{}, fmt.Errorf("AP")
 	}
+	if err := checkBitmap(pool); err != nil {
+		return SwapResult{}, err
+	}
 	desireX = new(big.Int).Set(desireX)
+	// uint128 amount of the contract
+	evm.RequireUint128(desireX)
 
 	highPt = calc.Min(highPt, pool.RightMostPt)
 
@@ -28,6 +44,7 @@ func SwapY2XDesireX(
 
 	liquidityX := new(big.Int).Set(pool.LiquidityX)
 	liquidity := new(big.Int).Set(pool.Liquidity)
+	feeScales := newFeeScales(pool)
 
 	finished := false
 	sqrtRate_96, _ := calc.GetSqrtPrice(1)
@@ -35,22 +52,26 @@ func SwapY2XDesireX(
 	currentPoint := pool.CurrentPoint
 	fee := int64(pool.Fee)
 
+	var events SwapEvents
+	var limitOrders []LimitOrderPoint
+
 	orderData := InitY2X(
 		pool.Liquidities,
 		pool.LimitOrders,
 		pool.CurrentPoint,
 	)
+	orderData.Bitmap = pool.Bitmap
 
 	for currentPoint < highPt && !finished {
 		if orderData.IsLimitOrder(currentPoint) {
 			// clear limit order first
 			currX := orderData.UnsafeGetLimitSellingX()
-			costY, acquireX := swapmathdesire.Y2XAtPrice(desireX, sqrtPrice_96, currX)
+			costY, acquireX := swapmathdesire.Y2XAtPriceEVM(evm, desireX, sqrtPrice_96, currX)
 
 			if acquireX.Cmp(desireX) >= 0 {
 				finished = true
 			}
-			feeAmount := calc.MulDivCeil(
+			feeAmount := evm.MulDivCeil(
 				costY,
 				new(big.Int).SetInt64(fee),
 				new(big.Int).SetInt64(1e6-fee),
@@ -59,7 +80,22 @@ func SwapY2XDesireX(
 			desireX.Sub(desireX, acquireX)
 			amountY.Add(amountY, new(big.Int).Add(costY, feeAmount))
 			amountX.Add(amountX, acquireX)
+			limitOrders = append(limitOrders, orderData.UnsafeGetLimitOrder().sellX(evm, costY, acquireX))
 			orderData.ConsumeLimitOrder(true)
+			events.LimitOrders++
+			feeScaleDelta, feeCharged := feeScales.addY(feeAmount, liquidity)
+			trace.add(TraceStep{
+				Kind:              StepLimitOrder,
+				FromPoint:         currentPoint,
+				ToPoint:           currentPoint,
+				AmountX:           acquireX,
+				AmountY:           new(big.Int).Add(costY, feeAmount),
+				FeeAmount:         feeAmount,
+				FeeCharged:        feeCharged,
+				FeeScaleDelta_128: feeScaleDelta,
+				Liquidity:         liquidity,
+				LiquidityX:        liquidityX,
+			})
 		}
 
 		if finished {
@@ -67,6 +103,7 @@ func SwapY2XDesireX(
 		}
 
 		nextPoint := orderData.MoveY2X(currentPoint, pointDelta)
+		events.BitmapWords++
 		if nextPoint > highPt {
 			nextPoint = highPt
 		}
@@ -76,8 +113,18 @@ func SwapY2XDesireX(
 			currentPoint = nextPoint
 			sqrtPrice_96, _ = calc.GetSqrtPrice(currentPoint)
 			if orderData.IsLiquidity(currentPoint) {
+				feeScales.pass(orderData.UnsafeGetLiquidityPoint())
 				delta := orderData.UnsafeGetDeltaLiquidity()
-				liquidity.Add(liquidity, delta)
+				liquidity.Set(evm.AddDelta(liquidity, delta))
+				events.Endpoints++
+				trace.add(TraceStep{
+					Kind:           StepEndpoint,
+					FromPoint:      currentPoint,
+					ToPoint:        currentPoint,
+					LiquidityDelta: delta,
+					Liquidity:      liquidity,
+					LiquidityX:     liquidity,
+				})
 				liquidityX = liquidity
 			}
 		} else {
@@ -89,10 +136,11 @@ func SwapY2XDesireX(
 					CurrentPoint: currentPoint,
 					SqrtPrice_96: sqrtPrice_96,
 				}
-				retState := swapmathdesire.Y2XRange(st, nextPoint, sqrtRate_96, desireX)
+				retState := swapmathdesire.Y2XRangeEVM(evm, st, nextPoint, sqrtRate_96, desireX)
+				events.Ranges++
 
 				finished = retState.Finished
-				feeAmount := calc.MulDivCeil(
+				feeAmount := evm.MulDivCeil(
 					retState.CostY,
 					new(big.Int).SetInt64(fee),
 					new(big.Int).SetInt64(1e6-fee),
@@ -105,14 +153,37 @@ func SwapY2XDesireX(
 				currentPoint = retState.FinalPt
 				sqrtPrice_96 = retState.SqrtFinalPrice_96
 				liquidityX = retState.LiquidityX
+				feeScaleDelta, feeCharged := feeScales.addY(feeAmount, liquidity)
+				trace.add(TraceStep{
+					Kind:              StepRange,
+					FromPoint:         st.CurrentPoint,
+					ToPoint:           currentPoint,
+					AmountX:           retState.AcquireX,
+					AmountY:           new(big.Int).Add(retState.CostY, feeAmount),
+					FeeAmount:         feeAmount,
+					FeeCharged:        feeCharged,
+					FeeScaleDelta_128: feeScaleDelta,
+					Liquidity:         liquidity,
+					LiquidityX:        liquidityX,
+				})
 			} else {
 				finished = true
 			}
 
 			if currentPoint == nextPoint {
 				if orderData.IsLiquidity(nextPoint) {
+					feeScales.pass(orderData.UnsafeGetLiquidityPoint())
 					delta := orderData.UnsafeGetDeltaLiquidity()
-					liquidity.Add(liquidity, delta)
+					liquidity.Set(evm.AddDelta(liquidity, delta))
+					events.Endpoints++
+					trace.add(TraceStep{
+						Kind:           StepEndpoint,
+						FromPoint:      currentPoint,
+						ToPoint:        currentPoint,
+						LiquidityDelta: delta,
+						Liquidity:      liquidity,
+						LiquidityX:     liquidity,
+					})
 				}
 				liquidityX = liquidity
 			}
@@ -120,11 +191,47 @@ func SwapY2XDesireX(
 	}
 
 	swapResult := SwapResult{
-		CurrentPoint: currentPoint,
-		Liquidity:    liquidity,
-		LiquidityX:   liquidityX,
-		AmountX:      amountX,
-		AmountY:      amountY,
+		CurrentPoint:  currentPoint,
+		Liquidity:     liquidity,
+		LiquidityX:    liquidityX,
+		AmountX:       amountX,
+		AmountY:       amountY,
+		AmountRemain:  calc.MaxBigInt(desireX, big.NewInt(0)),
+		Events:        events,
+		LimitOrders:   limitOrders,
+		FeeScaleX_128: feeScales.x,
+		FeeScaleY_128: feeScales.y,
+		FeeXCharged:   feeScales.chargedX,
+		FeeYCharged:   feeScales.chargedY,
+		Endpoints:     feeScales.endpoints,
+		StopReason: getStopReason(
+			desireX,
+			currentPoint >= highPt,
+			highPt == pool.RightMostPt,
+			orderData.noMoreY2X(currentPoint),
+		),
+	}
+	return swapResult, nil
+}
+
+// SwapY2XDesireXStrict is the same as SwapY2XDesireX, but returns
+// a *DesireNotSatisfiedError together with the result if the acquired
+// tokenX is less than desireX
+func SwapY2XDesireXStrict(
+	desireX *big.Int,
+	highPt int,
+	pool PoolInfo,
+) (SwapResult, error) {
+	swapResult, err := SwapY2XDesireX(desireX, highPt, pool)
+	if err != nil {
+		return swapResult, err
+	}
+	if swapResult.AmountRemain.Cmp(big.NewInt(0)) > 0 {
+		return swapResult, &DesireNotSatisfiedError{
+			Desire:  new(big.Int).Set(desireX),
+			Acquire: new(big.Int).Set(swapResult.AmountX),
+			Remain:  new(big.Int).Set(swapResult.AmountRemain),
+		}
 	}
 	return swapResult, nil
 }
@@ -195,3 +195,65 @@ func TestSwapY2XDesire5(t *testing.T) {
 		t.Fatalf("amount x not equal (%s, %s)", swapAmount.AmountX.String(), "251597283132")
 	}
 }
+
+func TestSwapY2XDesireStrict1(t *testing.T) {
+	poolInfo := getPoolInfoY2X()
+	var amount big.Int
+	amount.SetString("100000000000000000000000", 10)
+	swapAmount, err := SwapY2XDesireXStrict(&amount, 1100, poolInfo)
+	notSatisfied, ok := err.(*DesireNotSatisfiedError)
+	if !ok {
+		t.Fatalf("expect DesireNotSatisfiedError, got %v", err)
+	}
+	remain, _ := new(big.Int).SetString("99999999999748402716868", 10)
+	if notSatisfied.Remain.Cmp(remain) != 0 {
+		t.Fatalf("remain not equal (%s, %s)", notSatisfied.Remain.String(), remain.String())
+	}
+	if swapAmount.AmountRemain.Cmp(remain) != 0 {
+		t.Fatalf("amount remain not equal (%s, %s)", swapAmount.AmountRemain.String(), remain.String())
+	}
+}
+
+func TestSwapY2XDesireStrict2(t *testing.T) {
+	poolInfo := getPoolInfoY2X()
+	var amount big.Int
+	amount.SetString("228316826682", 10)
+	swapAmount, err := SwapY2XDesireXStrict(&amount, 1100, poolInfo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if swapAmount.AmountRemain.Sign() != 0 {
+		t.Fatalf("amount remain not zero (%s)", swapAmount.AmountRemain.String())
+	}
+}
+
+func TestSwapY2XStopReason(t *testing.T) {
+	cases := []struct {
+		amount      string
+		highPt      int
+		rightMostPt int
+		reason      StopReason
+		remain      string
+	}{
+		{"211374358247", 1100, 800000, StopAmountRunOut, "0"},
+		{"1", 1100, 800000, StopAmountDust, "1"},
+		{"410079196782", 1100, 800000, StopBoundaryPt, "198704838535"},
+		{"410079196782", 800000, 1100, StopMostPt, "198704838535"},
+		{"410079196782", 800000, 800000, StopLiquidityRunOut, "62452719262"},
+	}
+	for idx, c := range cases {
+		poolInfo := getPoolInfoY2X()
+		poolInfo.RightMostPt = c.rightMostPt
+		amount, _ := new(big.Int).SetString(c.amount, 10)
+		swapResult, _ := SwapY2X(amount, c.highPt, poolInfo)
+		if amount.String() != c.amount {
+			t.Fatalf("case %d: input amount modified (%s)", idx, amount.String())
+		}
+		if swapResult.StopReason != c.reason {
+			t.Fatalf("case %d: stop reason not equal (%s, %s)", idx, swapResult.StopReason, c.reason)
+		}
+		if swapResult.AmountRemain.String() != c.remain {
+			t.Fatalf("case %d: amount remain not equal (%s, %s)", idx, swapResult.AmountRemain.String(), c.remain)
+		}
+	}
+}
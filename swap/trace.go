@@ -0,0 +1,119 @@
+package swap
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// StepKind is the kind of a step in a swap trace
+type StepKind int
+
+const (
+	// trade with limit order on a point
+	StepLimitOrder StepKind = iota
+	// trade with liquidity in a range of points
+	StepRange
+	// cross a liquidity endpoint
+	StepEndpoint
+)
+
+func (kind StepKind) String() string {
+	switch kind {
+	case StepLimitOrder:
+		return "LimitOrder"
+	case StepRange:
+		return "Range"
+	case StepEndpoint:
+		return "Endpoint"
+	}
+	return "Unknown"
+}
+
+// TraceStep records one step of a swap
+type TraceStep struct {
+	Kind StepKind
+	// point before and after this step
+	FromPoint int
+	ToPoint   int
+	// tokenX and tokenY paid (fee included) or acquired in this step,
+	// nil for StepEndpoint
+	AmountX *big.Int
+	AmountY *big.Int
+	// fee charged in this step, in tokenX for x2y and in tokenY for y2x
+	FeeAmount *big.Int
+	// part of FeeAmount charged by the protocol, and fee scale added for
+	// liquidity of this step by the rest, nil for StepEndpoint
+	FeeCharged        *big.Int
+	FeeScaleDelta_128 *big.Int
+	// liquidity delta of the endpoint, only for StepEndpoint
+	LiquidityDelta *big.Int
+	// liquidity and liquidityX after this step
+	Liquidity  *big.Int
+	LiquidityX *big.Int
+}
+
+// Trace is the steps of a swap in order
+type Trace []TraceStep
+
+func copyBigInt(value *big.Int) *big.Int {
+	if value == nil {
+		return nil
+	}
+	return new(big.Int).Set(value)
+}
+
+// add appends a copy of step, nothing is done on a nil trace
+func (trace *Trace) add(step TraceStep) {
+	if trace == nil {
+		return
+	}
+	step.AmountX = copyBigInt(step.AmountX)
+	step.AmountY = copyBigInt(step.AmountY)
+	step.FeeAmount = copyBigInt(step.FeeAmount)
+	step.FeeCharged = copyBigInt(step.FeeCharged)
+	step.FeeScaleDelta_128 = copyBigInt(step.FeeScaleDelta_128)
+	step.LiquidityDelta = copyBigInt(step.LiquidityDelta)
+	step.Liquidity = copyBigInt(step.Liquidity)
+	step.LiquidityX = copyBigInt(step.LiquidityX)
+	*trace = append(*trace, step)
+}
+
+func (step TraceStep) String() string {
+	if step.Kind == StepEndpoint {
+		return fmt.Sprintf("%s %d -> %d delta %s liquidity %s liquidityX %s",
+			step.Kind, step.FromPoint, step.ToPoint,
+			step.LiquidityDelta, step.Liquidity, step.LiquidityX)
+	}
+	return fmt.Sprintf("%s %d -> %d amountX %s amountY %s fee %s liquidity %s liquidityX %s",
+		step.Kind, step.FromPoint, step.ToPoint,
+		step.AmountX, step.AmountY, step.FeeAmount, step.Liquidity, step.LiquidityX)
+}
+
+func (trace Trace) String() string {
+	lines := make([]string, len(trace))
+	for idx, step := range trace {
+		lines[idx] = fmt.Sprintf("%d: %s", idx, step)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TraceSwap is the same as Swap, but also returns every step of the swap
+func TraceSwap(swapType SwapType, amount *big.Int, boundaryPt int, pool PoolInfo) (SwapResult, Trace, error) {
+	trace := Trace{}
+	var swapResult SwapResult
+	var err error
+	switch swapType {
+	case X2Y:
+		swapResult, err = swapX2Y(amount, boundaryPt, pool, &trace)
+	case Y2X:
+		swapResult, err = swapY2X(amount, boundaryPt, pool, &trace)
+	case X2YDesireY:
+		swapResult, err = swapX2YDesireY(amount, boundaryPt, pool, &trace)
+	case Y2XDesireX:
+		swapResult, err = swapY2XDesireX(amount, boundaryPt, pool, &trace)
+	default:
+		err = fmt.Errorf("unknown swap type %d", swapType)
+	}
+	return swapResult, trace, err
+}
@@ -0,0 +1,40 @@
+package swap
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestTraceSwap(t *testing.T) {
+	for _, swapType := range []SwapType{X2Y, X2YDesireY} {
+		poolInfo := getPoolInfoDetailX2Y()
+		poolInfo.LiquidityX = big.NewInt(134333)
+		amount, _ := new(big.Int).SetString("372866052521", 10)
+		expect, _ := Swap(swapType, amount, -6789, poolInfo)
+		result, trace, err := TraceSwap(swapType, amount, -6789, poolInfo)
+		if err != nil {
+			t.Fatalf("trace swap failed: %v", err)
+		}
+		if result.AmountX.Cmp(expect.AmountX) != 0 || result.CurrentPoint != expect.CurrentPoint {
+			t.Fatalf("traced result not equal (%s, %d), (%s, %d)",
+				result.AmountX, result.CurrentPoint, expect.AmountX, expect.CurrentPoint)
+		}
+		amountX, amountY := big.NewInt(0), big.NewInt(0)
+		for _, step := range trace {
+			if step.Kind != StepEndpoint {
+				amountX.Add(amountX, step.AmountX)
+				amountY.Add(amountY, step.AmountY)
+			}
+		}
+		if amountX.Cmp(result.AmountX) != 0 || amountY.Cmp(result.AmountY) != 0 {
+			t.Fatalf("sum of steps not equal (%s, %s), (%s, %s)", amountX, amountY, result.AmountX, result.AmountY)
+		}
+		last := trace[len(trace)-1]
+		if last.LiquidityX.Cmp(result.LiquidityX) != 0 {
+			t.Fatalf("liquidityX of last step not equal (%s, %s)", last.LiquidityX, result.LiquidityX)
+		}
+		if len(trace) != result.Events.LimitOrders+result.Events.Ranges+result.Events.Endpoints {
+			t.Fatalf("steps not equal to events (%d, %+v)", len(trace), result.Events)
+		}
+	}
+}
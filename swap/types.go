@@ -2,6 +2,8 @@ package swap
 
 import (
 	"math/big"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
 )
 
 type SwapResult struct {
@@ -10,6 +12,27 @@ type SwapResult struct {
 	CurrentPoint int
 	Liquidity    *big.Int
 	LiquidityX   *big.Int
+	// unspent input amount for SwapX2Y / SwapY2X,
+	// or part of desired amount which can not be acquired
+	// before reaching the boundary point for desire swaps
+	AmountRemain *big.Int
+	// events which drive gas cost of the swap
+	Events SwapEvents
+	// why the swap stopped
+	StopReason StopReason
+	// limit orders traded in the swap in order, with selling amounts
+	// and earnings after the swap
+	LimitOrders []LimitOrderPoint
+	// fee scales of the pool after the swap
+	FeeScaleX_128 *big.Int
+	FeeScaleY_128 *big.Int
+	// fee charged by the protocol in the swap, FeeChargePercent of fee
+	// or all fee of limit orders traded without liquidity
+	FeeXCharged *big.Int
+	FeeYCharged *big.Int
+	// liquidity endpoints crossed in the swap in order,
+	// with fee scales outside after crossing
+	Endpoints []LiquidityPoint
 }
 
 type PoolInfo struct {
@@ -22,4 +45,27 @@ type PoolInfo struct {
 	LiquidityX   *big.Int
 	Liquidities  []LiquidityPoint
 	LimitOrders  []LimitOrderPoint
+	// optional, bitmap of initialized points maintained by caller with the same
+	// PointDelta, if nil each swap builds one from Liquidities and LimitOrders,
+	// which costs a pass over both, BuildPointBitmap once to reuse it among swaps
+	Bitmap *PointBitmap
+	// optional fee scales of the pool (feeScaleX_128, feeScaleY_128),
+	// fee earned per 2^-128 liquidity since the pool is created, nil means zero
+	FeeScaleX_128 *big.Int
+	FeeScaleY_128 *big.Int
+	// percent of fee charged by the protocol (feeChargePercent), 0 to distribute
+	// all fee to liquidity
+	FeeChargePercent int
+	// optional fee charged by the protocol and not yet collected
+	// (totalFeeXCharged, totalFeeYCharged), nil means zero
+	TotalFeeXCharged *big.Int
+	TotalFeeYCharged *big.Int
+	// check values against solidity integer types like the contract,
+	// swaps, Mint / Burn and AddLimitOrder return *calc.Revert on overflow
+	StrictEVM bool
+}
+
+// evm returns the integer checks used by calls on pool
+func (pool PoolInfo) evm() calc.EVM {
+	return calc.EVM{Strict: pool.StrictEVM}
 }
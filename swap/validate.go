@@ -0,0 +1,100 @@
+package swap
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Validate checks that pool can be swapped on, it returns all problems
+// found joined by errors.Join, or nil.
+// liquidity at current point is checked against liquidity deltas only if
+// deltas sum to zero, in which case Liquidities is the whole distribution
+func (pool PoolInfo) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if pool.PointDelta <= 0 {
+		fail("pointDelta %d is not positive", pool.PointDelta)
+		// points can not be checked without pointDelta
+		return errors.Join(errs...)
+	}
+	if pool.Fee <= 0 || pool.Fee >= 1e6 {
+		fail("fee %d not in (0, 1000000)", pool.Fee)
+	}
+	if err := checkBitmap(pool); err != nil {
+		errs = append(errs, err)
+	}
+	if pool.FeeChargePercent < 0 || pool.FeeChargePercent > 100 {
+		fail("feeChargePercent %d not in [0, 100]", pool.FeeChargePercent)
+	}
+	if pool.LeftMostPt >= pool.RightMostPt {
+		fail("leftMostPt %d not less than rightMostPt %d", pool.LeftMostPt, pool.RightMostPt)
+	}
+	if pool.CurrentPoint < pool.LeftMostPt || pool.CurrentPoint > pool.RightMostPt {
+		fail("currentPoint %d not in [%d, %d]", pool.CurrentPoint, pool.LeftMostPt, pool.RightMostPt)
+	}
+	if pool.Liquidity == nil || pool.LiquidityX == nil {
+		fail("liquidity or liquidityX is missing")
+	} else if pool.LiquidityX.Sign() < 0 || pool.LiquidityX.Cmp(pool.Liquidity) > 0 {
+		fail("liquidityX %s not in [0, liquidity %s]", pool.LiquidityX, pool.Liquidity)
+	}
+
+	sum := big.NewInt(0)
+	liquidity := big.NewInt(0)
+	negative := false
+	for idx, point := range pool.Liquidities {
+		if point.Point%pool.PointDelta != 0 {
+			fail("liquidity point %d not times of pointDelta %d", point.Point, pool.PointDelta)
+		}
+		if idx > 0 && pool.Liquidities[idx-1].Point >= point.Point {
+			fail("liquidities not in ascending order at point %d", point.Point)
+		}
+		if point.LiqudityDelta == nil {
+			fail("liquidity delta is missing at point %d", point.Point)
+			continue
+		}
+		sum.Add(sum, point.LiqudityDelta)
+		if sum.Sign() < 0 {
+			negative = true
+		}
+		if point.Point <= pool.CurrentPoint {
+			liquidity.Set(sum)
+		}
+	}
+	if sum.Sign() == 0 {
+		if negative {
+			fail("liquidity becomes negative")
+		}
+		if pool.Liquidity != nil && liquidity.Cmp(pool.Liquidity) != 0 {
+			fail("liquidity %s not equal to %s from liquidities at current point", pool.Liquidity, liquidity)
+		}
+	}
+
+	for idx := range pool.LimitOrders {
+		limitOrder := &pool.LimitOrders[idx]
+		point := limitOrder.Point
+		if point%pool.PointDelta != 0 {
+			fail("limit order point %d not times of pointDelta %d", point, pool.PointDelta)
+		}
+		if idx > 0 && pool.LimitOrders[idx-1].Point >= point {
+			fail("limit orders not in ascending order at point %d", point)
+		}
+		if (limitOrder.SellingX != nil && limitOrder.SellingX.Sign() < 0) ||
+			(limitOrder.SellingY != nil && limitOrder.SellingY.Sign() < 0) {
+			fail("negative limit order at point %d", point)
+		}
+		if hasSellingX(limitOrder) && point < pool.CurrentPoint {
+			fail("limit order selling tokenX at point %d below currentPoint", point)
+		}
+		if hasSellingY(limitOrder) && point > pool.CurrentPoint {
+			fail("limit order selling tokenY at point %d above currentPoint", point)
+		}
+		if hasSellingX(limitOrder) && hasSellingY(limitOrder) {
+			fail("limit order selling both tokenX and tokenY at point %d", point)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,27 @@
+package swap
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if err := getPoolInfoX2Y().Validate(); err != nil {
+		t.Fatalf("valid pool failed: %v", err)
+	}
+
+	poolInfo := getPoolInfoX2Y()
+	poolInfo.Liquidity = big.NewInt(100)
+	poolInfo.LimitOrders = append(poolInfo.LimitOrders, LimitOrderPoint{SellingY: big.NewInt(1), Point: 2000})
+	poolInfo.Liquidities[1].Point = -8001
+	err := poolInfo.Validate()
+	if err == nil {
+		t.Fatalf("invalid pool passed")
+	}
+	for _, problem := range []string{"liquidityX", "not times of pointDelta", "not equal", "above currentPoint"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Fatalf("problem %q not reported: %v", problem, err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package swaptest
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/poolkey"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// leftMostPt and rightMostPt of built pools, as iZiSwapPool for every fee tier
+const (
+	leftMostPt  = -800000
+	rightMostPt = 800000
+)
+
+// Builder builds a hand-crafted swap.PoolInfo from liquidity ranges
+// and limit orders, Liquidity of the pool is computed from the ranges
+// covering CurrentPoint
+type Builder struct {
+	fee          int
+	pointDelta   int
+	currentPoint int
+	liquidityX   *big.Int
+	deltas       map[int]*big.Int
+	limitOrders  map[int]*swap.LimitOrderPoint
+	err          error
+}
+
+// NewBuilder returns a builder of pool with fee,
+// pointDelta is decided by fee as iZiSwapFactory
+func NewBuilder(fee int) *Builder {
+	pointDelta, err := poolkey.PointDelta(fee)
+	return &Builder{
+		fee:         fee,
+		pointDelta:  pointDelta,
+		deltas:      map[int]*big.Int{},
+		limitOrders: map[int]*swap.LimitOrderPoint{},
+		err:         err,
+	}
+}
+
+func (builder *Builder) fail(format string, args ...interface{}) *Builder {
+	if builder.err == nil {
+		builder.err = fmt.Errorf(format, args...)
+	}
+	return builder
+}
+
+func (builder *Builder) checkPoint(point int) bool {
+	if builder.pointDelta == 0 || point%builder.pointDelta != 0 {
+		builder.fail("point %d is not times of pointDelta %d", point, builder.pointDelta)
+		return false
+	}
+	if point < leftMostPt || point > rightMostPt {
+		builder.fail("point %d not in [%d, %d]", point, leftMostPt, rightMostPt)
+		return false
+	}
+	return true
+}
+
+// CurrentPoint sets current point of the pool, 0 by default
+func (builder *Builder) CurrentPoint(point int) *Builder {
+	builder.currentPoint = point
+	return builder
+}
+
+// LiquidityX sets liquidity of tokenX at current point, 0 by default
+func (builder *Builder) LiquidityX(liquidityX *big.Int) *Builder {
+	builder.liquidityX = new(big.Int).Set(liquidityX)
+	return builder
+}
+
+// AddLiquidity adds liquidity in [leftPt, rightPt)
+func (builder *Builder) AddLiquidity(leftPt, rightPt int, liquidity *big.Int) *Builder {
+	if !builder.checkPoint(leftPt) || !builder.checkPoint(rightPt) {
+		return builder
+	}
+	if leftPt >= rightPt || liquidity.Sign() <= 0 {
+		return builder.fail("invalid liquidity %s in [%d, %d)", liquidity, leftPt, rightPt)
+	}
+	for _, end := range [2]int{leftPt, rightPt} {
+		if builder.deltas[end] == nil {
+			builder.deltas[end] = big.NewInt(0)
+		}
+	}
+	builder.deltas[leftPt].Add(builder.deltas[leftPt], liquidity)
+	builder.deltas[rightPt].Sub(builder.deltas[rightPt], liquidity)
+	return builder
+}
+
+func (builder *Builder) limitOrder(point int) *swap.LimitOrderPoint {
+	limitOrder := builder.limitOrders[point]
+	if limitOrder == nil {
+		limitOrder = &swap.LimitOrderPoint{Point: point}
+		builder.limitOrders[point] = limitOrder
+	}
+	return limitOrder
+}
+
+// SellX places a limit order selling amount of tokenX on point
+func (builder *Builder) SellX(point int, amount *big.Int) *Builder {
+	if builder.checkPoint(point) {
+		limitOrder := builder.limitOrder(point)
+		limitOrder.SellingX = addAmount(limitOrder.SellingX, amount)
+	}
+	return builder
+}
+
+// SellY places a limit order selling amount of tokenY on point
+func (builder *Builder) SellY(point int, amount *big.Int) *Builder {
+	if builder.checkPoint(point) {
+		limitOrder := builder.limitOrder(point)
+		limitOrder.SellingY = addAmount(limitOrder.SellingY, amount)
+	}
+	return builder
+}
+
+func addAmount(value, amount *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int).Set(amount)
+	}
+	return new(big.Int).Add(value, amount)
+}
+
+// Build returns the pool, or the first error of building,
+// limit orders must sell tokenY below current point and tokenX above it,
+// on current point at most one of tokenX and tokenY can be sold.
+// endpoints whose deltas sum to zero are kept, as the pool contract keeps
+// them in the bitmap. the pool built is checked by swap.PoolInfo.Validate
+func (builder *Builder) Build() (swap.PoolInfo, error) {
+	if builder.err != nil {
+		return swap.PoolInfo{}, builder.err
+	}
+	pool := swap.PoolInfo{
+		CurrentPoint: builder.currentPoint,
+		PointDelta:   builder.pointDelta,
+		LeftMostPt:   leftMostPt,
+		RightMostPt:  rightMostPt,
+		Fee:          builder.fee,
+		Liquidity:    big.NewInt(0),
+		LiquidityX:   big.NewInt(0),
+		Liquidities:  []swap.LiquidityPoint{},
+		LimitOrders:  []swap.LimitOrderPoint{},
+	}
+	for point, delta := range builder.deltas {
+		pool.Liquidities = append(pool.Liquidities, swap.LiquidityPoint{
+			LiqudityDelta: new(big.Int).Set(delta),
+			Point:         point,
+		})
+		if point <= builder.currentPoint {
+			pool.Liquidity.Add(pool.Liquidity, delta)
+		}
+	}
+	sort.Slice(pool.Liquidities, func(i, j int) bool {
+		return pool.Liquidities[i].Point < pool.Liquidities[j].Point
+	})
+	for point, limitOrder := range builder.limitOrders {
+		sellingX := limitOrder.SellingX != nil && limitOrder.SellingX.Sign() > 0
+		sellingY := limitOrder.SellingY != nil && limitOrder.SellingY.Sign() > 0
+		if (sellingX && point < builder.currentPoint) || (sellingY && point > builder.currentPoint) || (sellingX && sellingY) {
+			return swap.PoolInfo{}, fmt.Errorf("invalid limit order on point %d with current point %d", point, builder.currentPoint)
+		}
+		pool.LimitOrders = append(pool.LimitOrders, *limitOrder)
+	}
+	sort.Slice(pool.LimitOrders, func(i, j int) bool {
+		return pool.LimitOrders[i].Point < pool.LimitOrders[j].Point
+	})
+	if builder.liquidityX != nil {
+		if builder.liquidityX.Sign() < 0 || builder.liquidityX.Cmp(pool.Liquidity) > 0 {
+			return swap.PoolInfo{}, fmt.Errorf("liquidityX %s not in [0, liquidity %s]", builder.liquidityX, pool.Liquidity)
+		}
+		pool.LiquidityX.Set(builder.liquidityX)
+	}
+	if err := pool.Validate(); err != nil {
+		return swap.PoolInfo{}, err
+	}
+	return pool, nil
+}
+
+// MustBuild is the same as Build but panics on error
+func (builder *Builder) MustBuild() swap.PoolInfo {
+	pool, err := builder.Build()
+	if err != nil {
+		panic(err)
+	}
+	return pool
+}
@@ -0,0 +1,200 @@
+// Package swaptest generates swap.PoolInfo values for tests of code built on the sdk,
+// by seeded random generators or by hand with Builder
+package swaptest
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/library/calc"
+	"github.com/izumiFinance/iZiSwap-SDK-go/poolkey"
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// Fees are the fee tiers enabled in iZiSwapFactory
+var Fees = []int{100, 400, 2000, 10000}
+
+// Shape is the distribution of liquidity around current point
+type Shape int
+
+const (
+	// positions spread over the whole width with similar liquidity
+	Uniform Shape = iota
+	// narrow positions close to current point, deeper near current point
+	Concentrated
+	// few positions with gaps between them, current point may have no liquidity
+	Sparse
+)
+
+func (shape Shape) String() string {
+	switch shape {
+	case Uniform:
+		return "Uniform"
+	case Concentrated:
+		return "Concentrated"
+	case Sparse:
+		return "Sparse"
+	}
+	return "Unknown"
+}
+
+// Config of a Generator, zero values use the defaults
+type Config struct {
+	// fee tier of generated pools, random in Fees if 0
+	Fee   int
+	Shape Shape
+	// number of liquidity positions, 8 by default
+	Positions int
+	// liquidity of a position is at most MaxLiquidity, 1e18 by default
+	MaxLiquidity *big.Int
+	// probability of a limit order on each point (times of pointDelta) in width
+	LimitOrderDensity float64
+	// limit order sells at most MaxLimitOrder, 1e18 by default
+	MaxLimitOrder *big.Int
+	// positions and limit orders are in current point ± Width * pointDelta,
+	// clipped to the points of the pool, 200 by default
+	Width int
+}
+
+// Generator generates random valid pools, the same seed and config
+// always generate the same pools
+type Generator struct {
+	config Config
+	rnd    *rand.Rand
+}
+
+// NewGenerator returns a generator of pools with config,
+// it fails if Fee is not in Fees or a value of config is out of range
+func NewGenerator(seed int64, config Config) (*Generator, error) {
+	if config.Fee != 0 {
+		if _, err := poolkey.PointDelta(config.Fee); err != nil {
+			return nil, err
+		}
+	}
+	switch config.Shape {
+	case Uniform, Concentrated, Sparse:
+	default:
+		return nil, fmt.Errorf("unknown shape %d", config.Shape)
+	}
+	if config.Positions < 0 {
+		return nil, fmt.Errorf("negative positions %d", config.Positions)
+	}
+	if config.Width < 0 {
+		return nil, fmt.Errorf("negative width %d", config.Width)
+	}
+	if config.LimitOrderDensity < 0 || config.LimitOrderDensity > 1 {
+		return nil, fmt.Errorf("limit order density %v not in [0, 1]", config.LimitOrderDensity)
+	}
+	if config.MaxLiquidity != nil && config.MaxLiquidity.Sign() <= 0 {
+		return nil, fmt.Errorf("maxLiquidity %s is not positive", config.MaxLiquidity)
+	}
+	if config.MaxLimitOrder != nil && config.MaxLimitOrder.Sign() <= 0 {
+		return nil, fmt.Errorf("maxLimitOrder %s is not positive", config.MaxLimitOrder)
+	}
+	if config.Positions == 0 {
+		config.Positions = 8
+	}
+	if config.MaxLiquidity == nil {
+		config.MaxLiquidity = big.NewInt(1e18)
+	}
+	if config.MaxLimitOrder == nil {
+		config.MaxLimitOrder = big.NewInt(1e18)
+	}
+	if config.Width == 0 {
+		config.Width = 200
+	}
+	return &Generator{config: config, rnd: rand.New(rand.NewSource(seed))}, nil
+}
+
+// RandomPoolInfo returns a pool generated with seed and default config
+func RandomPoolInfo(seed int64) (swap.PoolInfo, error) {
+	generator, err := NewGenerator(seed, Config{})
+	if err != nil {
+		return swap.PoolInfo{}, err
+	}
+	return generator.PoolInfo()
+}
+
+// amount returns a random value in [1, max]
+func (generator *Generator) amount(max *big.Int) *big.Int {
+	value := new(big.Int).Rand(generator.rnd, max)
+	return value.Add(value, big.NewInt(1))
+}
+
+// position returns [leftPt, rightPt) of a position in pointDelta
+func (generator *Generator) position(center int) (int, int) {
+	width := generator.config.Width
+	switch generator.config.Shape {
+	case Concentrated:
+		// most positions cover center, with width of a few pointDelta
+		left := center - 1 - int(generator.rnd.ExpFloat64()*float64(width)/16)
+		right := center + 1 + int(generator.rnd.ExpFloat64()*float64(width)/16)
+		return left, right
+	case Sparse:
+		// short positions on random places
+		left := center + generator.rnd.Intn(2*width) - width
+		return left, left + generator.rnd.Intn(4) + 1
+	}
+	left := center + generator.rnd.Intn(2*width) - width
+	return left, left + generator.rnd.Intn(width/2+1) + 1
+}
+
+// PoolInfo generates a pool, points of liquidities and limit orders are
+// times of pointDelta, limit orders sell tokenY below current point and
+// tokenX above it, LiquidityX is in [0, Liquidity].
+// it returns the error of Builder.Build if the pool is invalid
+func (generator *Generator) PoolInfo() (swap.PoolInfo, error) {
+	config := generator.config
+	fee := config.Fee
+	if fee == 0 {
+		fee = Fees[generator.rnd.Intn(len(Fees))]
+	}
+	builder := NewBuilder(fee)
+	pointDelta := builder.pointDelta
+	// range of points of the pool, in pointDelta
+	lowest, highest := leftMostPt/pointDelta, rightMostPt/pointDelta
+	center := generator.rnd.Intn(2*config.Width+1) - config.Width
+	center = calc.Max(lowest, calc.Min(center, highest-1))
+	currentPoint := center*pointDelta + generator.rnd.Intn(pointDelta)
+	builder.CurrentPoint(currentPoint)
+
+	for i := 0; i < config.Positions; i++ {
+		left, right := generator.position(center)
+		left, right = calc.Max(left, lowest), calc.Min(right, highest)
+		if left >= right {
+			continue
+		}
+		liquidity := generator.amount(config.MaxLiquidity)
+		if config.Shape == Concentrated {
+			// deeper near current point
+			liquidity.Div(liquidity, big.NewInt(int64(right-left)))
+			liquidity.Add(liquidity, big.NewInt(1))
+		}
+		builder.AddLiquidity(left*pointDelta, right*pointDelta, liquidity)
+	}
+
+	if config.LimitOrderDensity > 0 {
+		for mapPt := calc.Max(center-config.Width, lowest); mapPt <= calc.Min(center+config.Width, highest); mapPt++ {
+			if generator.rnd.Float64() >= config.LimitOrderDensity {
+				continue
+			}
+			point := mapPt * pointDelta
+			amount := generator.amount(config.MaxLimitOrder)
+			if point < currentPoint || (point == currentPoint && generator.rnd.Intn(2) == 0) {
+				builder.SellY(point, amount)
+			} else {
+				builder.SellX(point, amount)
+			}
+		}
+	}
+
+	pool, err := builder.Build()
+	if err != nil {
+		return swap.PoolInfo{}, err
+	}
+	if pool.Liquidity.Sign() > 0 {
+		pool.LiquidityX.Rand(generator.rnd, new(big.Int).Add(pool.Liquidity, big.NewInt(1)))
+	}
+	return pool, nil
+}
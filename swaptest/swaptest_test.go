@@ -0,0 +1,152 @@
+package swaptest
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/izumiFinance/iZiSwap-SDK-go/swap"
+)
+
+// checkPool checks the pool is valid for swap
+func checkPool(t *testing.T, pool swap.PoolInfo) {
+	liquidity := big.NewInt(0)
+	for idx, point := range pool.Liquidities {
+		if point.Point%pool.PointDelta != 0 {
+			t.Fatalf("liquidity point %d not times of pointDelta %d", point.Point, pool.PointDelta)
+		}
+		if point.Point < pool.LeftMostPt || point.Point > pool.RightMostPt {
+			t.Fatalf("liquidity point %d not in [%d, %d]", point.Point, pool.LeftMostPt, pool.RightMostPt)
+		}
+		if idx > 0 && pool.Liquidities[idx-1].Point >= point.Point {
+			t.Fatalf("liquidities not sorted at %d", idx)
+		}
+		if point.Point <= pool.CurrentPoint {
+			liquidity.Add(liquidity, point.LiqudityDelta)
+		}
+	}
+	if liquidity.Cmp(pool.Liquidity) != 0 {
+		t.Fatalf("liquidity not equal (%s, %s)", pool.Liquidity, liquidity)
+	}
+	if pool.LiquidityX.Sign() < 0 || pool.LiquidityX.Cmp(pool.Liquidity) > 0 {
+		t.Fatalf("liquidityX %s not in [0, %s]", pool.LiquidityX, pool.Liquidity)
+	}
+	for idx, limitOrder := range pool.LimitOrders {
+		if limitOrder.Point%pool.PointDelta != 0 {
+			t.Fatalf("limit order point %d not times of pointDelta %d", limitOrder.Point, pool.PointDelta)
+		}
+		if limitOrder.Point < pool.LeftMostPt || limitOrder.Point > pool.RightMostPt {
+			t.Fatalf("limit order point %d not in [%d, %d]", limitOrder.Point, pool.LeftMostPt, pool.RightMostPt)
+		}
+		if idx > 0 && pool.LimitOrders[idx-1].Point >= limitOrder.Point {
+			t.Fatalf("limit orders not sorted at %d", idx)
+		}
+		if (limitOrder.SellingX != nil && limitOrder.Point < pool.CurrentPoint) ||
+			(limitOrder.SellingY != nil && limitOrder.Point > pool.CurrentPoint) {
+			t.Fatalf("limit order on wrong side at point %d", limitOrder.Point)
+		}
+	}
+}
+
+// generate returns a pool of a generator with seed and config
+func generate(t *testing.T, seed int64, config Config) swap.PoolInfo {
+	generator, err := NewGenerator(seed, config)
+	if err != nil {
+		t.Fatalf("new generator failed: %v", err)
+	}
+	pool, err := generator.PoolInfo()
+	if err != nil {
+		t.Fatalf("generate pool failed: %v", err)
+	}
+	return pool
+}
+
+func TestGenerator(t *testing.T) {
+	for _, shape := range []Shape{Uniform, Concentrated, Sparse} {
+		for seed := int64(0); seed < 50; seed++ {
+			config := Config{Shape: shape, LimitOrderDensity: 0.05}
+			pool := generate(t, seed, config)
+			checkPool(t, pool)
+			if again := generate(t, seed, config); !reflect.DeepEqual(pool, again) {
+				t.Fatalf("%s pool of seed %d not reproducible", shape, seed)
+			}
+		}
+	}
+	pool := generate(t, 1, Config{Fee: 400})
+	if pool.Fee != 400 || pool.PointDelta != 8 {
+		t.Fatalf("fee tier not equal (%d, %d)", pool.Fee, pool.PointDelta)
+	}
+	if len(pool.LimitOrders) != 0 {
+		t.Fatalf("limit orders generated with zero density")
+	}
+	// points out of the pool are clipped
+	for seed := int64(0); seed < 10; seed++ {
+		checkPool(t, generate(t, seed, Config{Fee: 10000, Width: 10000, LimitOrderDensity: 0.01}))
+	}
+
+	for _, config := range []Config{
+		{Fee: 3000},
+		{Shape: Shape(3)},
+		{Positions: -1},
+		{Width: -1},
+		{LimitOrderDensity: 1.5},
+		{MaxLiquidity: big.NewInt(0)},
+		{MaxLimitOrder: big.NewInt(-1)},
+	} {
+		if _, err := NewGenerator(1, config); err == nil {
+			t.Fatalf("config %+v should fail", config)
+		}
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	pool, err := NewBuilder(2000).
+		CurrentPoint(100).
+		AddLiquidity(-5000, 5000, big.NewInt(200000)).
+		AddLiquidity(-3000, 3000, big.NewInt(100000)).
+		LiquidityX(big.NewInt(100000)).
+		SellY(-1600, big.NewInt(100000000000)).
+		SellX(1000, big.NewInt(120000000000)).
+		Build()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	checkPool(t, pool)
+	if pool.Liquidity.Cmp(big.NewInt(300000)) != 0 || len(pool.Liquidities) != 4 || len(pool.LimitOrders) != 2 {
+		t.Fatalf("pool not expected: %+v", pool)
+	}
+
+	// endpoints of adjacent ranges with the same liquidity stay in the pool
+	pool, err = NewBuilder(2000).
+		AddLiquidity(-400, 0, big.NewInt(1000)).
+		AddLiquidity(0, 400, big.NewInt(1000)).
+		Build()
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	if len(pool.Liquidities) != 3 || pool.Liquidities[1].Point != 0 || pool.Liquidities[1].LiqudityDelta.Sign() != 0 {
+		t.Fatalf("endpoint with zero delta not kept: %+v", pool.Liquidities)
+	}
+
+	if _, err := NewBuilder(2000).AddLiquidity(-5000, 5001, big.NewInt(1)).Build(); err == nil {
+		t.Fatalf("point not times of pointDelta should fail")
+	}
+	if _, err := NewBuilder(2000).CurrentPoint(100).SellX(-40, big.NewInt(1)).Build(); err == nil {
+		t.Fatalf("selling tokenX below current point should fail")
+	}
+	if _, err := NewBuilder(2000).LiquidityX(big.NewInt(1)).Build(); err == nil {
+		t.Fatalf("liquidityX more than liquidity should fail")
+	}
+	if _, err := NewBuilder(2000).AddLiquidity(-800040, 0, big.NewInt(1)).Build(); err == nil {
+		t.Fatalf("point below leftMostPt should fail")
+	}
+	if _, err := NewBuilder(2000).CurrentPoint(-800000).SellX(800040, big.NewInt(1)).Build(); err == nil {
+		t.Fatalf("point above rightMostPt should fail")
+	}
+	if _, err := NewBuilder(2000).CurrentPoint(800001).Build(); err == nil {
+		t.Fatalf("current point above rightMostPt should fail")
+	}
+	if _, err := NewBuilder(3000).Build(); err == nil {
+		t.Fatalf("fee not enabled should fail")
+	}
+}